@@ -0,0 +1,117 @@
+package config
+
+import "time"
+
+type Config struct {
+	Port            string        `yaml:"port" toml:"port" env:"PORT"`
+	Secret          string        `yaml:"secret" toml:"secret" env:"SECRET"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout" toml:"shutdownTimeout" env:"SHUTDOWN_TIMEOUT"`
+	Database        Database      `yaml:"database" toml:"database"`
+	Pagination      Pagination    `yaml:"pagination" toml:"pagination"`
+	Metrics         Metrics       `yaml:"metrics" toml:"metrics"`
+	Tracing         Tracing       `yaml:"tracing" toml:"tracing"`
+	Health          Health        `yaml:"health" toml:"health"`
+	Migrations      Migrations    `yaml:"migrations" toml:"migrations"`
+	Idempotency     Idempotency   `yaml:"idempotency" toml:"idempotency"`
+	Trash           Trash         `yaml:"trash" toml:"trash"`
+	Post            Post          `yaml:"post" toml:"post"`
+	Media           Media         `yaml:"media" toml:"media"`
+	ContentFilter   ContentFilter `yaml:"contentFilter" toml:"contentFilter"`
+	Spam            Spam          `yaml:"spam" toml:"spam"`
+	Preference      Preference    `yaml:"preference" toml:"preference"`
+	LinkPreview     LinkPreview   `yaml:"linkPreview" toml:"linkPreview"`
+	Jobs            Jobs          `yaml:"jobs" toml:"jobs"`
+	Features        Features      `yaml:"features" toml:"features"`
+}
+
+type Database struct {
+	Url                    string        `yaml:"url" toml:"url" env:"DATABASE_URL"`
+	Name                   string        `yaml:"name" toml:"name" env:"DATABASE_NAME"`
+	AppName                string        `yaml:"appName" toml:"appName" env:"DATABASE_APP_NAME"`
+	MaxPoolSize            uint64        `yaml:"maxPoolSize" toml:"maxPoolSize" env:"DATABASE_MAX_POOL_SIZE"`
+	MinPoolSize            uint64        `yaml:"minPoolSize" toml:"minPoolSize" env:"DATABASE_MIN_POOL_SIZE"`
+	MaxConnIdleTime        time.Duration `yaml:"maxConnIdleTime" toml:"maxConnIdleTime" env:"DATABASE_MAX_CONN_IDLE_TIME"`
+	ConnectTimeout         time.Duration `yaml:"connectTimeout" toml:"connectTimeout" env:"DATABASE_CONNECT_TIMEOUT"`
+	SocketTimeout          time.Duration `yaml:"socketTimeout" toml:"socketTimeout" env:"DATABASE_SOCKET_TIMEOUT"`
+	ServerSelectionTimeout time.Duration `yaml:"serverSelectionTimeout" toml:"serverSelectionTimeout" env:"DATABASE_SERVER_SELECTION_TIMEOUT"`
+	ReadConcern            string        `yaml:"readConcern" toml:"readConcern" env:"DATABASE_READ_CONCERN"`
+	WriteConcern           string        `yaml:"writeConcern" toml:"writeConcern" env:"DATABASE_WRITE_CONCERN"`
+}
+
+type Pagination struct {
+	DefaultLimit int32 `yaml:"defaultLimit" toml:"defaultLimit" env:"PAGINATION_DEFAULT_LIMIT"`
+	MaxLimit     int32 `yaml:"maxLimit" toml:"maxLimit" env:"PAGINATION_MAX_LIMIT"`
+}
+
+type Metrics struct {
+	Port string `yaml:"port" toml:"port" env:"METRICS_PORT"`
+}
+
+type Tracing struct {
+	Exporter    string `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER"`
+	ServiceName string `yaml:"serviceName" toml:"serviceName" env:"OTEL_SERVICE_NAME"`
+}
+
+type Health struct {
+	Interval time.Duration `yaml:"interval" toml:"interval" env:"HEALTH_CHECK_INTERVAL"`
+}
+
+type Migrations struct {
+	OnStartup bool `yaml:"onStartup" toml:"onStartup" env:"MIGRATE_ON_STARTUP"`
+	Only      bool `yaml:"-" toml:"-"`
+}
+
+type Idempotency struct {
+	TTL time.Duration `yaml:"ttl" toml:"ttl" env:"IDEMPOTENCY_TTL"`
+}
+
+type Trash struct {
+	Retention time.Duration `yaml:"retention" toml:"retention" env:"TRASH_RETENTION"`
+}
+
+type Post struct {
+	MaxPins int `yaml:"maxPins" toml:"maxPins" env:"POST_MAX_PINS"`
+}
+
+type Media struct {
+	MaxPerPost   int      `yaml:"maxPerPost" toml:"maxPerPost" env:"MEDIA_MAX_PER_POST"`
+	MaxSize      int64    `yaml:"maxSize" toml:"maxSize" env:"MEDIA_MAX_SIZE"`
+	AllowedTypes []string `yaml:"allowedTypes" toml:"allowedTypes" env:"MEDIA_ALLOWED_TYPES"`
+	AllowedHosts []string `yaml:"allowedHosts" toml:"allowedHosts" env:"MEDIA_ALLOWED_HOSTS"`
+}
+
+type ContentFilter struct {
+	RulesFile string `yaml:"rulesFile" toml:"rulesFile" env:"CONTENT_FILTER_RULES_FILE"`
+}
+
+type Spam struct {
+	Window          time.Duration `yaml:"window" toml:"window" env:"SPAM_WINDOW"`
+	MaxVelocity     int           `yaml:"maxVelocity" toml:"maxVelocity" env:"SPAM_MAX_VELOCITY"`
+	QuarantineScore int           `yaml:"quarantineScore" toml:"quarantineScore" env:"SPAM_QUARANTINE_SCORE"`
+	RejectScore     int           `yaml:"rejectScore" toml:"rejectScore" env:"SPAM_REJECT_SCORE"`
+}
+
+type Preference struct {
+	HalfLife time.Duration `yaml:"halfLife" toml:"halfLife" env:"PREFERENCE_HALF_LIFE"`
+}
+
+type LinkPreview struct {
+	MaxLinks   int           `yaml:"maxLinks" toml:"maxLinks" env:"LINK_PREVIEW_MAX_LINKS"`
+	Timeout    time.Duration `yaml:"timeout" toml:"timeout" env:"LINK_PREVIEW_TIMEOUT"`
+	MaxBytes   int64         `yaml:"maxBytes" toml:"maxBytes" env:"LINK_PREVIEW_MAX_BYTES"`
+	CacheTTL   time.Duration `yaml:"cacheTtl" toml:"cacheTtl" env:"LINK_PREVIEW_CACHE_TTL"`
+	FailureTTL time.Duration `yaml:"failureTtl" toml:"failureTtl" env:"LINK_PREVIEW_FAILURE_TTL"`
+}
+
+type Jobs struct {
+	CounterReconcileInterval time.Duration `yaml:"counterReconcileInterval" toml:"counterReconcileInterval" env:"COUNTER_RECONCILE_INTERVAL"`
+	TrashPurgeInterval       time.Duration `yaml:"trashPurgeInterval" toml:"trashPurgeInterval" env:"TRASH_PURGE_INTERVAL"`
+	PostSchedulerInterval    time.Duration `yaml:"postSchedulerInterval" toml:"postSchedulerInterval" env:"POST_SCHEDULER_INTERVAL"`
+	ContentFilterReload      time.Duration `yaml:"contentFilterReload" toml:"contentFilterReload" env:"CONTENT_FILTER_RELOAD_INTERVAL"`
+}
+
+type Features struct {
+	Metrics            bool `yaml:"metrics" toml:"metrics" env:"FEATURE_METRICS"`
+	Reflection         bool `yaml:"reflection" toml:"reflection" env:"ENABLE_REFLECTION"`
+	PreferenceTracking bool `yaml:"preferenceTracking" toml:"preferenceTracking" env:"FEATURE_PREFERENCE_TRACKING"`
+}
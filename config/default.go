@@ -0,0 +1,58 @@
+package config
+
+import "time"
+
+func Default() Config {
+	return Config{
+		Port:            "50052",
+		ShutdownTimeout: 30 * time.Second,
+		Database: Database{
+			Name:           "Post",
+			AppName:        "nine-tails-fox",
+			MaxPoolSize:    100,
+			ConnectTimeout: 30 * time.Second,
+		},
+		Pagination: Pagination{
+			DefaultLimit: 10,
+			MaxLimit:     100,
+		},
+		Metrics: Metrics{Port: "9090"},
+		Tracing: Tracing{Exporter: "none"},
+		Health:  Health{Interval: 10 * time.Second},
+		Migrations: Migrations{
+			OnStartup: true,
+		},
+		Idempotency: Idempotency{TTL: 24 * time.Hour},
+		Trash:       Trash{Retention: 30 * 24 * time.Hour},
+		Post:        Post{MaxPins: 3},
+		Media: Media{
+			MaxPerPost:   4,
+			MaxSize:      50 << 20,
+			AllowedTypes: []string{"image/jpeg", "image/png", "image/webp", "image/gif", "video/mp4", "video/webm"},
+		},
+		Spam: Spam{
+			Window:          10 * time.Minute,
+			MaxVelocity:     30,
+			QuarantineScore: 50,
+			RejectScore:     120,
+		},
+		Preference: Preference{HalfLife: 30 * 24 * time.Hour},
+		LinkPreview: LinkPreview{
+			MaxLinks:   3,
+			Timeout:    3 * time.Second,
+			MaxBytes:   512 << 10,
+			CacheTTL:   24 * time.Hour,
+			FailureTTL: time.Hour,
+		},
+		Jobs: Jobs{
+			CounterReconcileInterval: time.Hour,
+			TrashPurgeInterval:       time.Hour,
+			PostSchedulerInterval:    30 * time.Second,
+			ContentFilterReload:      time.Minute,
+		},
+		Features: Features{
+			Metrics:            true,
+			PreferenceTracking: true,
+		},
+	}
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+func Load(args []string) (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to load .env : %w", err)
+	}
+
+	cfg := Default()
+
+	flags := flag.NewFlagSet("nine-tails-fox", flag.ContinueOnError)
+	file := flags.String("config", os.Getenv("CONFIG_FILE"), "path to a yaml or toml config file")
+	port := flags.String("port", "", "gRPC port")
+	databaseUrl := flags.String("database-url", "", "MongoDB connection string")
+	databaseName := flags.String("database-name", "", "MongoDB database name")
+	metricsPort := flags.String("metrics-port", "", "metrics HTTP port")
+	reflection := flags.Bool("reflection", false, "enable gRPC server reflection")
+	migrate := flags.Bool("migrate", false, "apply pending database migrations and exit")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if *file != "" {
+		if err := loadFile(*file, &cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := loadEnv(reflect.ValueOf(&cfg).Elem()); err != nil {
+		return nil, err
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Port = *port
+		case "database-url":
+			cfg.Database.Url = *databaseUrl
+		case "database-name":
+			cfg.Database.Name = *databaseName
+		case "metrics-port":
+			cfg.Metrics.Port = *metricsPort
+		case "reflection":
+			cfg.Features.Reflection = *reflection
+		case "migrate":
+			cfg.Migrations.Only = *migrate
+		default:
+			break
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file : %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".toml":
+		err = toml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file extension %q", filepath.Ext(path))
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s : %w", path, err)
+	}
+	return nil
+}
+
+func loadEnv(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct && field.Type() != reflect.TypeOf(time.Duration(0)) {
+			if err := loadEnv(field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		key := t.Field(i).Tag.Get("env")
+		if key == "" {
+			continue
+		}
+
+		val, ok := os.LookupEnv(key)
+		if !ok || val == "" {
+			continue
+		}
+
+		if err := setValue(field, val); err != nil {
+			return fmt.Errorf("invalid value for %s : %w", key, err)
+		}
+	}
+	return nil
+}
+
+func setValue(field reflect.Value, val string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(val)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(val, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported config type %s", field.Type())
+		}
+
+		values := []string{}
+		for _, data := range strings.Split(val, ",") {
+			if data = strings.TrimSpace(data); data != "" {
+				values = append(values, data)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	case reflect.Uint, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(val, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	default:
+		return fmt.Errorf("unsupported config type %s", field.Kind())
+	}
+	return nil
+}
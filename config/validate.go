@@ -0,0 +1,116 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/forum-gamers/nine-tails-fox/tracing"
+)
+
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Port == "" {
+		errs = append(errs, errors.New("port is required"))
+	}
+
+	if c.Secret == "" {
+		errs = append(errs, errors.New("secret is required (SECRET)"))
+	}
+
+	if c.Database.Url == "" {
+		errs = append(errs, errors.New("database url is required (DATABASE_URL)"))
+	}
+
+	if c.Database.Name == "" {
+		errs = append(errs, errors.New("database name is required (DATABASE_NAME)"))
+	}
+
+	if c.Database.MaxPoolSize > 0 && c.Database.MinPoolSize > c.Database.MaxPoolSize {
+		errs = append(errs, fmt.Errorf("database minPoolSize (%d) must not exceed maxPoolSize (%d)", c.Database.MinPoolSize, c.Database.MaxPoolSize))
+	}
+
+	if c.Database.ConnectTimeout <= 0 {
+		errs = append(errs, errors.New("database connectTimeout must be positive"))
+	}
+
+	switch c.Database.ReadConcern {
+	case "", "local", "available", "majority", "linearizable", "snapshot":
+	default:
+		errs = append(errs, fmt.Errorf("database readConcern %q is not a valid read concern level", c.Database.ReadConcern))
+	}
+
+	if c.Pagination.DefaultLimit < 1 || c.Pagination.MaxLimit < 1 {
+		errs = append(errs, errors.New("pagination limits must be positive"))
+	} else if c.Pagination.DefaultLimit > c.Pagination.MaxLimit {
+		errs = append(errs, fmt.Errorf("pagination defaultLimit (%d) must not exceed maxLimit (%d)", c.Pagination.DefaultLimit, c.Pagination.MaxLimit))
+	}
+
+	if c.Features.Metrics && c.Metrics.Port == "" {
+		errs = append(errs, errors.New("metrics port is required when metrics are enabled"))
+	}
+
+	switch tracing.Exporter(c.Tracing.Exporter) {
+	case "", tracing.None, tracing.Stdout, tracing.OTLP:
+	default:
+		errs = append(errs, fmt.Errorf("tracing exporter must be one of none,stdout,otlp, got %q", c.Tracing.Exporter))
+	}
+
+	if c.Health.Interval <= 0 {
+		errs = append(errs, errors.New("health interval must be positive"))
+	}
+
+	if c.Idempotency.TTL <= 0 {
+		errs = append(errs, errors.New("idempotency ttl must be positive"))
+	}
+
+	if c.Trash.Retention <= 0 {
+		errs = append(errs, errors.New("trash retention must be positive"))
+	}
+
+	if c.Post.MaxPins < 0 {
+		errs = append(errs, errors.New("post maxPins must not be negative"))
+	}
+
+	if c.Media.MaxPerPost < 0 || c.Media.MaxSize < 0 {
+		errs = append(errs, errors.New("media limits must not be negative"))
+	}
+
+	// without hosts any url is accepted as media, so it has to be set explicitly
+	if len(c.Media.AllowedHosts) < 1 {
+		errs = append(errs, errors.New("media allowedHosts is required (MEDIA_ALLOWED_HOSTS)"))
+	}
+
+	if c.Spam.Window <= 0 {
+		errs = append(errs, errors.New("spam window must be positive"))
+	}
+
+	if c.Spam.MaxVelocity < 0 || c.Spam.QuarantineScore < 0 || c.Spam.RejectScore < 0 {
+		errs = append(errs, errors.New("spam limits must not be negative"))
+	} else if c.Spam.QuarantineScore > 0 && c.Spam.RejectScore > 0 && c.Spam.QuarantineScore >= c.Spam.RejectScore {
+		errs = append(errs, fmt.Errorf("spam quarantineScore (%d) must be below rejectScore (%d)", c.Spam.QuarantineScore, c.Spam.RejectScore))
+	}
+
+	if c.Preference.HalfLife <= 0 {
+		errs = append(errs, errors.New("preference halfLife must be positive"))
+	}
+
+	if c.LinkPreview.MaxLinks < 0 {
+		errs = append(errs, errors.New("linkPreview maxLinks must not be negative"))
+	} else if c.LinkPreview.MaxLinks > 0 && (c.LinkPreview.Timeout <= 0 || c.LinkPreview.MaxBytes <= 0 || c.LinkPreview.CacheTTL <= 0 || c.LinkPreview.FailureTTL <= 0) {
+		errs = append(errs, errors.New("linkPreview timeout, maxBytes, cacheTtl and failureTtl must be positive"))
+	}
+
+	if c.Jobs.CounterReconcileInterval < 0 || c.Jobs.TrashPurgeInterval < 0 || c.Jobs.PostSchedulerInterval < 0 || c.Jobs.ContentFilterReload < 0 {
+		errs = append(errs, errors.New("job intervals must not be negative"))
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("shutdownTimeout must be positive"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration : %w", errors.Join(errs...))
+	}
+	return nil
+}
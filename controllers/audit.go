@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/generated"
+	protobuf "github.com/forum-gamers/nine-tails-fox/generated/audit"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
+	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AuditService struct {
+	protobuf.UnimplementedAuditServiceServer
+	GetUser   func(ctx context.Context) user.User
+	AuditRepo audit.AuditRepo
+}
+
+func (s *AuditService) QueryAuditLog(ctx context.Context, in *protobuf.AuditParams) (*protobuf.AuditResp, error) {
+	if s.GetUser(ctx).AccountType != "Admin" {
+		return nil, status.Error(codes.PermissionDenied, "Forbidden")
+	}
+
+	query := audit.Query{
+		Page:       int(in.Page),
+		Limit:      int(in.Limit),
+		ActorId:    in.ActorId,
+		Action:     in.Action,
+		TargetType: in.TargetType,
+	}
+
+	if in.TargetId != "" {
+		targetId, err := primitive.ObjectIDFromHex(in.TargetId)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid targetId")
+		}
+		query.TargetId = targetId
+	}
+
+	var err error
+	if query.From, err = parseOptionalTime(in.From); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "from must be an RFC3339 timestamp")
+	}
+
+	if query.To, err = parseOptionalTime(in.To); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "to must be an RFC3339 timestamp")
+	}
+
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	data, err := s.AuditRepo.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protobuf.AuditResp{
+		TotalData: int64(data[0].TotalData),
+		Page:      in.Page,
+		Limit:     in.Limit,
+		Data:      generated.ParseAuditEntriesToProto(data),
+	}, nil
+}
+
+func parseOptionalTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// releasedSnapshot returns a copy of a hidden post, comment or reply as it
+// looks once it is visible again.
+func releasedSnapshot(data any) any {
+	switch data := data.(type) {
+	case post.Post:
+		data.HiddenAt, data.HiddenBy = nil, ""
+		return data
+	case comment.Comment:
+		data.HiddenAt, data.HiddenBy = nil, ""
+		return data
+	case comment.ReplyComment:
+		data.HiddenAt, data.HiddenBy = nil, ""
+		return data
+	default:
+		return data
+	}
+}
+
+// removedSnapshot returns a copy of a post, comment or reply as it looks after
+// being hidden or soft deleted, for the after side of an audit entry.
+func removedSnapshot(data any, hidden bool, by string, at time.Time) any {
+	switch data := data.(type) {
+	case post.Post:
+		if hidden {
+			data.HiddenAt, data.HiddenBy = &at, by
+		} else {
+			data.DeletedAt, data.DeletedBy = &at, by
+		}
+		return data
+	case comment.Comment:
+		if hidden {
+			data.HiddenAt, data.HiddenBy = &at, by
+		} else {
+			data.DeletedAt, data.DeletedBy = &at, by
+		}
+		return data
+	case comment.ReplyComment:
+		if hidden {
+			data.HiddenAt, data.HiddenBy = &at, by
+		} else {
+			data.DeletedAt, data.DeletedBy = &at, by
+		}
+		return data
+	default:
+		return data
+	}
+}
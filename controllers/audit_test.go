@@ -0,0 +1,54 @@
+package controllers_test
+
+import (
+	"testing"
+	"time"
+
+	auditProto "github.com/forum-gamers/nine-tails-fox/generated/audit"
+	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	"google.golang.org/grpc/codes"
+)
+
+func TestQueryAuditLog(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	adminCtx := authAs(t, s, admin)
+	client := auditProto.NewAuditServiceClient(s.Conn)
+
+	_, err := client.QueryAuditLog(adminCtx, &auditProto.AuditParams{})
+	assertCode(t, err, codes.NotFound)
+
+	data := createPost(t, s, ctx, nil)
+	_, err = postProto.NewPostServiceClient(s.Conn).DeletePost(ctx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+
+	result, err := client.QueryAuditLog(adminCtx, &auditProto.AuditParams{TargetId: data.XId})
+	mustNoError(t, err)
+	if result.TotalData != 1 || result.Data[0].ActorId != alice || result.Data[0].TargetId != data.XId {
+		t.Errorf("QueryAuditLog() = %v", result)
+	}
+
+	_, err = client.QueryAuditLog(adminCtx, &auditProto.AuditParams{ActorId: bob})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.QueryAuditLog(ctx, &auditProto.AuditParams{})
+	assertCode(t, err, codes.PermissionDenied)
+
+	now := time.Now()
+	tests := []struct {
+		name   string
+		params *auditProto.AuditParams
+	}{
+		{"invalid target id", &auditProto.AuditParams{TargetId: "invalid"}},
+		{"invalid from", &auditProto.AuditParams{From: "yesterday"}},
+		{"invalid to", &auditProto.AuditParams{To: "tomorrow"}},
+		{"inverted range", &auditProto.AuditParams{From: now.Format(time.RFC3339), To: now.Add(-time.Hour).Format(time.RFC3339)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.QueryAuditLog(adminCtx, tt.params)
+			assertCode(t, err, codes.InvalidArgument)
+		})
+	}
+}
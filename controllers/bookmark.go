@@ -5,9 +5,12 @@ import (
 
 	"github.com/forum-gamers/nine-tails-fox/generated"
 	protobuf "github.com/forum-gamers/nine-tails-fox/generated/bookmark"
+	"github.com/forum-gamers/nine-tails-fox/metrics"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
 	"github.com/forum-gamers/nine-tails-fox/pkg/base"
 	"github.com/forum-gamers/nine-tails-fox/pkg/bookmark"
 	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/relation"
 	"github.com/forum-gamers/nine-tails-fox/pkg/user"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/grpc/codes"
@@ -20,6 +23,8 @@ type BookmarkService struct {
 	PostRepo        post.PostRepo
 	BookmarkRepo    bookmark.BookmarkRepo
 	BookmarkService bookmark.BookmarkService
+	AuditService    audit.AuditService
+	RelationRepo    relation.RelationRepo
 }
 
 func (s *BookmarkService) CreateBookmark(ctx context.Context, req *protobuf.PostIdPayload) (*protobuf.Bookmark, error) {
@@ -38,21 +43,15 @@ func (s *BookmarkService) CreateBookmark(ctx context.Context, req *protobuf.Post
 	}
 
 	userId := s.GetUser(ctx).Id
-	var bookmarkData bookmark.Bookmark
-	if err := s.BookmarkRepo.FindByPostIdAndUserId(ctx, postId, userId, &bookmarkData); err != nil {
-		if e, ok := status.FromError(err); ok && e.Code() != codes.NotFound {
-			return nil, err
-		}
-	}
-
-	if bookmarkData.Id != primitive.NilObjectID {
-		return nil, status.Error(codes.AlreadyExists, "Conflict")
+	if err := checkBlocked(ctx, s.RelationRepo, postData.UserId, userId); err != nil {
+		return nil, err
 	}
 
 	data := s.BookmarkService.CreatePayload(postId, userId)
 	if err := s.BookmarkRepo.CreateOne(ctx, &data); err != nil {
 		return nil, err
 	}
+	metrics.BookmarksCreated.Inc()
 
 	return &protobuf.Bookmark{
 		XId:       data.Id.Hex(),
@@ -78,7 +77,13 @@ func (s *BookmarkService) DeleteBookmark(ctx context.Context, req *protobuf.IdPa
 		return nil, err
 	}
 
-	if err := s.BookmarkRepo.DeleteOneById(ctx, bookmarkId); err != nil {
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.BookmarkRepo.DeleteOneById(ctx, bookmarkId); err != nil {
+			return err
+		}
+
+		return s.AuditService.Record(ctx, s.GetUser(ctx), audit.ActionDeleteBookmark, audit.TargetBookmark, bookmarkId, data, nil)
+	}); err != nil {
 		return nil, err
 	}
 	return &protobuf.Messages{Message: "success"}, nil
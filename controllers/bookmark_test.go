@@ -0,0 +1,113 @@
+package controllers_test
+
+import (
+	"testing"
+
+	bookmarkProto "github.com/forum-gamers/nine-tails-fox/generated/bookmark"
+	relationProto "github.com/forum-gamers/nine-tails-fox/generated/relation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/idempotency"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCreateBookmark(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	bobCtx := authAs(t, s, bob)
+	client := bookmarkProto.NewBookmarkServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	bookmark, err := client.CreateBookmark(bobCtx, &bookmarkProto.PostIdPayload{PostId: data.XId})
+	mustNoError(t, err)
+	if bookmark.UserId != bob || bookmark.PostId != data.XId {
+		t.Errorf("CreateBookmark() = %v", bookmark)
+	}
+
+	_, err = client.CreateBookmark(bobCtx, &bookmarkProto.PostIdPayload{PostId: data.XId})
+	assertCode(t, err, codes.AlreadyExists)
+
+	_, err = client.CreateBookmark(bobCtx, &bookmarkProto.PostIdPayload{})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.CreateBookmark(bobCtx, &bookmarkProto.PostIdPayload{PostId: "invalid"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.CreateBookmark(bobCtx, &bookmarkProto.PostIdPayload{PostId: missingId()})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = relationProto.NewRelationServiceClient(s.Conn).Block(ctx, &relationProto.UserIdPayload{UserId: "carol"})
+	mustNoError(t, err)
+
+	_, err = client.CreateBookmark(authAs(t, s, "carol"), &bookmarkProto.PostIdPayload{PostId: data.XId})
+	assertCode(t, err, codes.PermissionDenied)
+}
+
+func TestCreateBookmarkIdempotencyKey(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := bookmarkProto.NewBookmarkServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+	keyCtx := metadata.AppendToOutgoingContext(ctx, idempotency.HEADERKEY, "key-1")
+
+	first, err := client.CreateBookmark(keyCtx, &bookmarkProto.PostIdPayload{PostId: data.XId})
+	mustNoError(t, err)
+
+	second, err := client.CreateBookmark(keyCtx, &bookmarkProto.PostIdPayload{PostId: data.XId})
+	mustNoError(t, err)
+	if second.XId != first.XId {
+		t.Errorf("replayed CreateBookmark() = %s, want %s", second.XId, first.XId)
+	}
+
+	_, err = client.CreateBookmark(keyCtx, &bookmarkProto.PostIdPayload{PostId: missingId()})
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestDeleteBookmark(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := bookmarkProto.NewBookmarkServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	bookmark, err := client.CreateBookmark(ctx, &bookmarkProto.PostIdPayload{PostId: data.XId})
+	mustNoError(t, err)
+
+	_, err = client.DeleteBookmark(ctx, &bookmarkProto.IdPayload{})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.DeleteBookmark(ctx, &bookmarkProto.IdPayload{XId: "invalid"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.DeleteBookmark(ctx, &bookmarkProto.IdPayload{XId: bookmark.XId})
+	mustNoError(t, err)
+
+	_, err = client.DeleteBookmark(ctx, &bookmarkProto.IdPayload{XId: bookmark.XId})
+	assertCode(t, err, codes.NotFound)
+	if len(s.Store.AuditLog) != 1 || s.Store.AuditLog[0].Action != "DeleteBookmark" {
+		t.Errorf("audit log = %v, want one DeleteBookmark entry", s.Store.AuditLog)
+	}
+}
+
+func TestGetMyBookmarks(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := bookmarkProto.NewBookmarkServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	_, err := client.GetMyBookmarks(ctx, &bookmarkProto.PaginationWithPostId{PostId: data.XId})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.CreateBookmark(ctx, &bookmarkProto.PostIdPayload{PostId: data.XId})
+	mustNoError(t, err)
+
+	result, err := client.GetMyBookmarks(ctx, &bookmarkProto.PaginationWithPostId{PostId: data.XId})
+	mustNoError(t, err)
+	if result.TotalData != 1 || result.Data[0].XId != data.XId {
+		t.Errorf("GetMyBookmarks() = %v", result)
+	}
+
+	_, err = client.GetMyBookmarks(ctx, &bookmarkProto.PaginationWithPostId{})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.GetMyBookmarks(ctx, &bookmarkProto.PaginationWithPostId{PostId: "invalid"})
+	assertCode(t, err, codes.InvalidArgument)
+}
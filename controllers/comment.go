@@ -2,11 +2,18 @@ package controllers
 
 import (
 	"context"
+	"time"
 
+	"github.com/forum-gamers/nine-tails-fox/filter"
 	"github.com/forum-gamers/nine-tails-fox/generated"
 	protobuf "github.com/forum-gamers/nine-tails-fox/generated/comment"
+	"github.com/forum-gamers/nine-tails-fox/metrics"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
 	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
+	"github.com/forum-gamers/nine-tails-fox/pkg/moderation"
 	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/relation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/spam"
 	"github.com/forum-gamers/nine-tails-fox/pkg/user"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/grpc/codes"
@@ -15,10 +22,16 @@ import (
 
 type CommentService struct {
 	protobuf.UnimplementedCommentServiceServer
-	GetUser        func(ctx context.Context) user.User
-	PostRepo       post.PostRepo
-	CommentRepo    comment.CommentRepo
-	CommentService comment.CommentService
+	GetUser           func(ctx context.Context) user.User
+	PostRepo          post.PostRepo
+	CommentRepo       comment.CommentRepo
+	CommentService    comment.CommentService
+	AuditService      audit.AuditService
+	ContentFilter     filter.ContentFilter
+	ReportRepo        moderation.ReportRepo
+	ModerationService moderation.ModerationService
+	SpamService       spam.SpamService
+	RelationRepo      relation.RelationRepo
 }
 
 func (s *CommentService) CreateComment(ctx context.Context, req *protobuf.CommentForm) (*protobuf.Comment, error) {
@@ -41,11 +54,50 @@ func (s *CommentService) CreateComment(ctx context.Context, req *protobuf.Commen
 		return nil, err
 	}
 
-	commentPayload := s.CommentService.CreatePayload(req.Text, postId, s.GetUser(ctx).Id)
-	if err := s.CommentRepo.CreateComment(ctx, &commentPayload); err != nil {
+	userId := s.GetUser(ctx).Id
+	if err := checkBlocked(ctx, s.RelationRepo, postData.UserId, userId); err != nil {
 		return nil, err
 	}
 
+	checked, err := s.ContentFilter.Check(req.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	verdict, err := checkSpam(ctx, s.SpamService, userId, moderation.TargetComment, checked.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	commentPayload := s.CommentService.CreatePayload(checked.Text, postId, userId)
+	quarantined := verdict.Outcome == spam.Quarantine
+	if quarantined {
+		commentPayload.HiddenAt, commentPayload.HiddenBy = &commentPayload.CreatedAt, moderation.SYSTEMREPORTER
+	}
+
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.CommentRepo.CreateComment(ctx, &commentPayload); err != nil {
+			return err
+		}
+
+		// quarantined comments are counted once a moderator releases them
+		if !quarantined {
+			if err := s.PostRepo.IncrementCounter(ctx, postId, post.CountComment, 1); err != nil {
+				return err
+			}
+		}
+
+		return reviewContent(ctx, s.SpamService, s.ReportRepo, s.ModerationService, moderation.Target{
+			TargetType: moderation.TargetComment,
+			TargetId:   commentPayload.Id,
+			PostId:     postId,
+			AuthorId:   commentPayload.UserId,
+		}, verdict, checked.Flags)
+	}); err != nil {
+		return nil, err
+	}
+	metrics.CommentsCreated.Inc()
+
 	return &protobuf.Comment{
 		XId:       commentPayload.Id.Hex(),
 		Text:      commentPayload.Text,
@@ -71,7 +123,31 @@ func (s *CommentService) DeleteComment(ctx context.Context, req *protobuf.Commen
 		return nil, err
 	}
 
-	if err := s.CommentRepo.DeleteOne(ctx, commentId); err != nil {
+	user := s.GetUser(ctx)
+	if data.UserId != user.Id && user.AccountType != "Admin" {
+		return nil, status.Error(codes.PermissionDenied, "Forbidden")
+	}
+
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		// read again inside the transaction so replies added since are counted
+		var current comment.Comment
+		if err := s.CommentRepo.FindById(ctx, commentId, &current); err != nil {
+			return err
+		}
+
+		deleted, err := s.CommentRepo.SoftDelete(ctx, commentId, user.Id)
+		if err != nil {
+			return err
+		}
+
+		if deleted == 1 {
+			if err := s.PostRepo.IncrementCounter(ctx, current.PostId, post.CountComment, -visibleThread(current)); err != nil {
+				return err
+			}
+		}
+
+		return s.AuditService.Record(ctx, user, audit.ActionDeleteComment, audit.TargetComment, commentId, data, removedSnapshot(data, false, user.Id, time.Now()))
+	}); err != nil {
 		return nil, err
 	}
 
@@ -84,7 +160,12 @@ func (s *CommentService) FindPostComment(ctx context.Context, in *protobuf.Pagin
 		return nil, status.Error(codes.InvalidArgument, "invalid objectId")
 	}
 
-	data, err := s.CommentRepo.FindPostComment(ctx, postId, struct {
+	excluded, err := s.RelationRepo.FindHiddenUserIds(ctx, s.GetUser(ctx).Id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.CommentRepo.FindPostComment(ctx, postId, excluded, struct {
 		Page  int
 		Limit int
 	}{Page: int(in.Page), Limit: int(in.Limit)})
@@ -0,0 +1,133 @@
+package controllers_test
+
+import (
+	"testing"
+
+	commentProto "github.com/forum-gamers/nine-tails-fox/generated/comment"
+	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	relationProto "github.com/forum-gamers/nine-tails-fox/generated/relation"
+	replyProto "github.com/forum-gamers/nine-tails-fox/generated/reply"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCreateComment(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	bobCtx := authAs(t, s, bob)
+	client := commentProto.NewCommentServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	comment, err := client.CreateComment(bobCtx, &commentProto.CommentForm{Text: "nice post", PostId: data.XId})
+	mustNoError(t, err)
+	if comment.UserId != bob || comment.PostId != data.XId || comment.Text != "nice post" {
+		t.Errorf("CreateComment() = %v", comment)
+	}
+
+	result, err := postProto.NewPostServiceClient(s.Conn).FindById(ctx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+	if result.CountComment != 1 {
+		t.Errorf("countComment = %d, want 1", result.CountComment)
+	}
+
+	tests := []struct {
+		name string
+		form *commentProto.CommentForm
+		want codes.Code
+	}{
+		{"missing text", &commentProto.CommentForm{PostId: data.XId}, codes.InvalidArgument},
+		{"missing post id", &commentProto.CommentForm{Text: "text"}, codes.InvalidArgument},
+		{"invalid post id", &commentProto.CommentForm{Text: "text", PostId: "invalid"}, codes.InvalidArgument},
+		{"missing post", &commentProto.CommentForm{Text: "text", PostId: missingId()}, codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreateComment(bobCtx, tt.form)
+			assertCode(t, err, tt.want)
+		})
+	}
+
+	t.Run("blocked by the author", func(t *testing.T) {
+		_, err := relationProto.NewRelationServiceClient(s.Conn).Block(ctx, &relationProto.UserIdPayload{UserId: bob})
+		mustNoError(t, err)
+
+		_, err = client.CreateComment(bobCtx, &commentProto.CommentForm{Text: "another one", PostId: data.XId})
+		assertCode(t, err, codes.PermissionDenied)
+	})
+}
+
+func TestDeleteComment(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	bobCtx := authAs(t, s, bob)
+	client := commentProto.NewCommentServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	comment, err := client.CreateComment(bobCtx, &commentProto.CommentForm{Text: "first", PostId: data.XId})
+	mustNoError(t, err)
+
+	_, err = replyProto.NewReplyServiceClient(s.Conn).CreateReply(ctx, &replyProto.CommentForm{Text: "thanks", CommentId: comment.XId})
+	mustNoError(t, err)
+
+	_, err = client.DeleteComment(ctx, &commentProto.CommentIdPayload{XId: comment.XId})
+	assertCode(t, err, codes.PermissionDenied)
+
+	_, err = client.DeleteComment(bobCtx, &commentProto.CommentIdPayload{})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.DeleteComment(bobCtx, &commentProto.CommentIdPayload{XId: missingId()})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.DeleteComment(bobCtx, &commentProto.CommentIdPayload{XId: comment.XId})
+	mustNoError(t, err)
+
+	_, err = client.DeleteComment(bobCtx, &commentProto.CommentIdPayload{XId: comment.XId})
+	assertCode(t, err, codes.NotFound)
+
+	result, err := postProto.NewPostServiceClient(s.Conn).FindById(ctx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+	if result.CountComment != 0 {
+		t.Errorf("countComment = %d, want 0 after deleting the thread", result.CountComment)
+	}
+
+	t.Run("admin", func(t *testing.T) {
+		comment, err := client.CreateComment(bobCtx, &commentProto.CommentForm{Text: "second", PostId: data.XId})
+		mustNoError(t, err)
+
+		_, err = client.DeleteComment(authAs(t, s, admin), &commentProto.CommentIdPayload{XId: comment.XId})
+		mustNoError(t, err)
+	})
+}
+
+func TestFindPostComment(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := commentProto.NewCommentServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	_, err := client.FindPostComment(ctx, &commentProto.PaginationWithPostId{PostId: data.XId})
+	assertCode(t, err, codes.NotFound)
+
+	for _, userId := range []string{bob, "carol"} {
+		_, err := client.CreateComment(authAs(t, s, userId), &commentProto.CommentForm{Text: "comment by " + userId, PostId: data.XId})
+		mustNoError(t, err)
+	}
+
+	result, err := client.FindPostComment(ctx, &commentProto.PaginationWithPostId{PostId: data.XId})
+	mustNoError(t, err)
+	if result.TotalData != 2 || len(result.Data) != 2 {
+		t.Errorf("FindPostComment() = %v", result)
+	}
+
+	_, err = relationProto.NewRelationServiceClient(s.Conn).Mute(ctx, &relationProto.UserIdPayload{UserId: bob})
+	mustNoError(t, err)
+
+	result, err = client.FindPostComment(ctx, &commentProto.PaginationWithPostId{PostId: data.XId})
+	mustNoError(t, err)
+	if result.TotalData != 1 || result.Data[0].UserId != "carol" {
+		t.Errorf("FindPostComment() after mute = %v", result)
+	}
+
+	_, err = client.FindPostComment(ctx, &commentProto.PaginationWithPostId{PostId: "invalid"})
+	assertCode(t, err, codes.InvalidArgument)
+}
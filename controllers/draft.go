@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/generated"
+	protobuf "github.com/forum-gamers/nine-tails-fox/generated/post"
+	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *PostService) SaveDraft(ctx context.Context, req *protobuf.DraftForm) (*protobuf.Post, error) {
+	if !h.IsValidPrivacy(req.Privacy) {
+		return nil, status.Error(codes.InvalidArgument, "Privacy must be on of Public,Private,Friend Only")
+	}
+
+	postStatus, publishAt, err := parsePublishAt(req.PublishAt)
+	if err != nil {
+		return nil, err
+	}
+
+	checked, err := s.ContentFilter.Check(req.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	if len(checked.Text) > 0 {
+		tags = s.PostService.GetPostTags(checked.Text)
+	}
+
+	medias, err := s.parseFiles(req.Files)
+	if err != nil {
+		return nil, err
+	}
+
+	data := s.PostService.CreatePostPayload(s.GetUser(ctx).Id, checked.Text, req.Privacy, req.AllowComment, medias, tags)
+	data.Status = postStatus
+	data.PublishAt = publishAt
+	data.Previews = s.PreviewService.Resolve(ctx, s.PreviewService.ExtractUrls(data.Text))
+
+	if err := s.PostRepo.Create(ctx, &data); err != nil {
+		return nil, err
+	}
+
+	return generated.ParsePostToProto(data), nil
+}
+
+func (s *PostService) ListDrafts(ctx context.Context, in *protobuf.Pagination) (*protobuf.PostRespWithMetadata, error) {
+	data, err := s.PostRepo.GetDrafts(ctx, s.GetUser(ctx).Id, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.PollService.ApplyViewer(ctx, s.GetUser(ctx).Id, data); err != nil {
+		return nil, err
+	}
+
+	return &protobuf.PostRespWithMetadata{
+		TotalData: int64(data[0].TotalData),
+		Page:      in.Page,
+		Limit:     in.Limit,
+		Data:      generated.ParsePostRespToProto(data),
+	}, nil
+}
+
+func (s *PostService) UpdateDraft(ctx context.Context, req *protobuf.UpdateDraftForm) (*protobuf.Post, error) {
+	data, err := s.findOwnDraft(ctx, req.XId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !h.IsValidPrivacy(req.Privacy) {
+		return nil, status.Error(codes.InvalidArgument, "Privacy must be on of Public,Private,Friend Only")
+	}
+
+	if data.Status, data.PublishAt, err = parsePublishAt(req.PublishAt); err != nil {
+		return nil, err
+	}
+
+	checked, err := s.ContentFilter.Check(req.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Tags = []string{}
+	if len(checked.Text) > 0 {
+		data.Tags = s.PostService.GetPostTags(checked.Text)
+	}
+	if data.Media, err = s.parseFiles(req.Files); err != nil {
+		return nil, err
+	}
+	data.Text = checked.Text
+	data.Previews = s.PreviewService.Resolve(ctx, s.PreviewService.ExtractUrls(data.Text))
+	data.AllowComment = req.AllowComment
+	data.Privacy = req.Privacy
+	data.UpdatedAt = time.Now()
+
+	if err := s.PostRepo.UpdateDraft(ctx, &data); err != nil {
+		return nil, err
+	}
+
+	return generated.ParsePostToProto(data), nil
+}
+
+func (s *PostService) PublishDraft(ctx context.Context, req *protobuf.PostIdPayload) (*protobuf.Post, error) {
+	data, err := s.findOwnDraft(ctx, req.XId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.publishDraft(ctx, &data, time.Now()); err != nil {
+		return nil, err
+	}
+	return generated.ParsePostToProto(data), nil
+}
+
+// PublishScheduled publishes a scheduled post that is due, for the scheduler.
+// A post the content filter now rejects goes back to the owner's drafts so it
+// is not retried on every run.
+func (s *PostService) PublishScheduled(ctx context.Context, data post.Post) error {
+	draft := data
+	err := s.publishDraft(ctx, &data, *data.PublishAt)
+	if status.Code(err) != codes.InvalidArgument {
+		return err
+	}
+
+	draft.Status = post.Draft
+	draft.PublishAt = nil
+	draft.UpdatedAt = time.Now()
+	if updateErr := s.PostRepo.UpdateDraft(ctx, &draft); updateErr != nil {
+		return updateErr
+	}
+	return err
+}
+
+func (s *PostService) publishDraft(ctx context.Context, data *post.Post, at time.Time) error {
+	data.Status = post.Published
+	data.PublishAt = nil
+	data.CreatedAt = at
+	data.UpdatedAt = at
+	return s.publish(ctx, data, func(ctx context.Context) error {
+		return s.PostRepo.Publish(ctx, data)
+	})
+}
+
+func (s *PostService) findOwnDraft(ctx context.Context, id string) (data post.Post, err error) {
+	if id == "" {
+		err = status.Error(codes.InvalidArgument, "_id is required")
+		return
+	}
+
+	postId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, "Invalid ObjectId")
+		return
+	}
+
+	if err = s.PostRepo.FindDraftById(ctx, postId, &data); err != nil {
+		return
+	}
+
+	if data.UserId != s.GetUser(ctx).Id {
+		err = status.Error(codes.PermissionDenied, "Forbidden")
+	}
+	return
+}
+
+func parsePublishAt(val string) (string, *time.Time, error) {
+	if val == "" {
+		return post.Draft, nil, nil
+	}
+
+	publishAt, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return "", nil, status.Error(codes.InvalidArgument, "publishAt must be an RFC3339 timestamp")
+	}
+
+	if !publishAt.After(time.Now()) {
+		return "", nil, status.Error(codes.InvalidArgument, "publishAt must be in the future")
+	}
+	return post.Scheduled, &publishAt, nil
+}
+
+func (s *PostService) parseFiles(files []*protobuf.FileHeader) ([]post.Media, error) {
+	medias := make([]post.Media, 0)
+	for _, file := range files {
+		medias = append(medias, post.Media{
+			Url:       file.Url,
+			Type:      file.ContentType,
+			Id:        file.FileId,
+			Width:     int(file.Width),
+			Height:    int(file.Height),
+			Duration:  file.Duration,
+			Size:      file.Size,
+			Blurhash:  file.Blurhash,
+			Alt:       file.Alt,
+			Sensitive: file.Sensitive,
+		})
+	}
+
+	if err := s.MediaRules.Validate(medias); err != nil {
+		return nil, err
+	}
+
+	for i := range medias {
+		medias[i].Type = post.MediaType(medias[i].Type)
+	}
+	return medias, nil
+}
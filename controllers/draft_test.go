@@ -0,0 +1,229 @@
+package controllers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/config"
+	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	"github.com/forum-gamers/nine-tails-fox/jobs"
+	"github.com/forum-gamers/nine-tails-fox/pkg/memory"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/testutil"
+	"google.golang.org/grpc/codes"
+)
+
+func TestSaveDraft(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	data, err := client.SaveDraft(ctx, &postProto.DraftForm{Text: "Draft Text", Privacy: "Public"})
+	mustNoError(t, err)
+	if data.Status != post.Draft || data.PublishAt != "" || len(data.Tags) != 2 {
+		t.Errorf("SaveDraft() = %v", data)
+	}
+
+	publishAt := time.Now().Add(time.Hour).Format(time.RFC3339)
+	data, err = client.SaveDraft(ctx, &postProto.DraftForm{Text: "scheduled", Privacy: "Public", PublishAt: publishAt})
+	mustNoError(t, err)
+	if data.Status != post.Scheduled || data.PublishAt == "" {
+		t.Errorf("SaveDraft(publishAt) = %v", data)
+	}
+
+	tests := []struct {
+		name string
+		form *postProto.DraftForm
+	}{
+		{"invalid privacy", &postProto.DraftForm{Text: "text", Privacy: "Secret"}},
+		{"invalid publishAt", &postProto.DraftForm{Text: "text", Privacy: "Public", PublishAt: "tomorrow"}},
+		{"publishAt in the past", &postProto.DraftForm{Text: "text", Privacy: "Public", PublishAt: time.Now().Add(-time.Hour).Format(time.RFC3339)}},
+		{"invalid media", &postProto.DraftForm{Text: "text", Privacy: "Public", Files: []*postProto.FileHeader{{ContentType: "text/html", Url: "https://example.com"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.SaveDraft(ctx, tt.form)
+			assertCode(t, err, codes.InvalidArgument)
+		})
+	}
+}
+
+func TestListDrafts(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	_, err := client.ListDrafts(ctx, &postProto.Pagination{})
+	assertCode(t, err, codes.NotFound)
+
+	draft, err := client.SaveDraft(ctx, &postProto.DraftForm{Text: "draft", Privacy: "Public"})
+	mustNoError(t, err)
+	createPost(t, s, ctx, nil)
+
+	result, err := client.ListDrafts(ctx, &postProto.Pagination{})
+	mustNoError(t, err)
+	if result.TotalData != 1 || result.Data[0].XId != draft.XId {
+		t.Errorf("ListDrafts() = %v", result)
+	}
+
+	_, err = client.ListDrafts(authAs(t, s, bob), &postProto.Pagination{})
+	assertCode(t, err, codes.NotFound)
+}
+
+func TestUpdateDraft(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	draft, err := client.SaveDraft(ctx, &postProto.DraftForm{Text: "draft", Privacy: "Public"})
+	mustNoError(t, err)
+
+	data, err := client.UpdateDraft(ctx, &postProto.UpdateDraftForm{XId: draft.XId, Text: "Updated Draft", Privacy: "Private"})
+	mustNoError(t, err)
+	if data.Text != "Updated Draft" || data.Privacy != "Private" || len(data.Tags) != 2 || data.Tags[0] != "updated" {
+		t.Errorf("UpdateDraft() = %v", data)
+	}
+
+	published := createPost(t, s, ctx, nil)
+	tests := []struct {
+		name   string
+		userId string
+		form   *postProto.UpdateDraftForm
+		want   codes.Code
+	}{
+		{"other user", bob, &postProto.UpdateDraftForm{XId: draft.XId, Text: "text", Privacy: "Public"}, codes.PermissionDenied},
+		{"missing id", alice, &postProto.UpdateDraftForm{Text: "text", Privacy: "Public"}, codes.InvalidArgument},
+		{"invalid privacy", alice, &postProto.UpdateDraftForm{XId: draft.XId, Text: "text", Privacy: "Secret"}, codes.InvalidArgument},
+		{"published post", alice, &postProto.UpdateDraftForm{XId: published.XId, Text: "text", Privacy: "Public"}, codes.NotFound},
+		{"missing draft", alice, &postProto.UpdateDraftForm{XId: missingId(), Text: "text", Privacy: "Public"}, codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.UpdateDraft(authAs(t, s, tt.userId), tt.form)
+			assertCode(t, err, tt.want)
+		})
+	}
+}
+
+func TestPublishDraft(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	draft, err := client.SaveDraft(ctx, &postProto.DraftForm{Text: "ready to go", Privacy: "Public"})
+	mustNoError(t, err)
+
+	_, err = client.PublishDraft(authAs(t, s, bob), &postProto.PostIdPayload{XId: draft.XId})
+	assertCode(t, err, codes.PermissionDenied)
+
+	data, err := client.PublishDraft(ctx, &postProto.PostIdPayload{XId: draft.XId})
+	mustNoError(t, err)
+	if data.Status != post.Published || data.XId != draft.XId {
+		t.Errorf("PublishDraft() = %v", data)
+	}
+
+	_, err = client.FindById(ctx, &postProto.PostIdPayload{XId: draft.XId})
+	mustNoError(t, err)
+
+	_, err = client.PublishDraft(ctx, &postProto.PostIdPayload{XId: draft.XId})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.PublishDraft(ctx, &postProto.PostIdPayload{XId: "invalid"})
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestScheduledPostsArePublished(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	draft := scheduleDue(t, s, ctx, "scheduled post")
+
+	mustNoError(t, s.Scheduler.Run(context.Background()))
+
+	_, err := postProto.NewPostServiceClient(s.Conn).FindById(ctx, &postProto.PostIdPayload{XId: draft.XId})
+	mustNoError(t, err)
+}
+
+func TestRateLimitedScheduledPostsStayDue(t *testing.T) {
+	s := newServer(t, func(cfg *config.Config) {
+		cfg.Spam.MaxVelocity = 1
+	})
+	ctx := authAs(t, s, alice)
+	draft := scheduleDue(t, s, ctx, "scheduled post")
+	createPost(t, s, ctx, nil)
+
+	mustNoError(t, s.Scheduler.Run(context.Background()))
+
+	if data := findStored(s, draft.XId); data.Status != post.Scheduled {
+		t.Errorf("status = %s, want %s until the user may post again", data.Status, post.Scheduled)
+	}
+}
+
+func TestRateLimitedScheduledPostsDoNotStarveOthers(t *testing.T) {
+	s := newServer(t, func(cfg *config.Config) {
+		cfg.Spam.MaxVelocity = 1
+	})
+	ctx := authAs(t, s, alice)
+	for i := 0; i < jobs.PUBLISHBATCHSIZE; i++ {
+		scheduleDue(t, s, ctx, fmt.Sprintf("scheduled post %d", i))
+	}
+	createPost(t, s, ctx, nil)
+	draft := scheduleDue(t, s, authAs(t, s, bob), "bob's scheduled post")
+
+	mustNoError(t, s.Scheduler.Run(context.Background()))
+
+	if data := findStored(s, draft.XId); data.Status != post.Published {
+		t.Errorf("status = %s, want %s behind a full batch of rate limited posts", data.Status, post.Published)
+	}
+}
+
+func TestScheduledPostFailureDoesNotAbortRun(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	broken := scheduleDue(t, s, ctx, "broken post")
+	draft := scheduleDue(t, s, ctx, "scheduled post")
+
+	var published []string
+	scheduler := jobs.NewPostScheduler(memory.NewPostRepo(s.Store), time.Minute, func(ctx context.Context, data post.Post) error {
+		if data.Id.Hex() == broken.XId {
+			return errors.New("connection reset")
+		}
+		published = append(published, data.Id.Hex())
+		return nil
+	})
+	mustNoError(t, scheduler.Run(context.Background()))
+
+	if len(published) != 1 || published[0] != draft.XId {
+		t.Errorf("published %v, want only %s", published, draft.XId)
+	}
+}
+
+// scheduleDue saves a draft scheduled for later and moves its publish time
+// into the past so the next scheduler run picks it up.
+func scheduleDue(t *testing.T, s *testutil.Server, ctx context.Context, text string) *postProto.Post {
+	t.Helper()
+
+	draft, err := postProto.NewPostServiceClient(s.Conn).SaveDraft(ctx, &postProto.DraftForm{
+		Text:      text,
+		Privacy:   "Public",
+		PublishAt: time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	mustNoError(t, err)
+
+	publishAt := time.Now().Add(-time.Minute)
+	findStored(s, draft.XId).PublishAt = &publishAt
+	return draft
+}
+
+func findStored(s *testutil.Server, id string) *post.Post {
+	for i := range s.Store.Posts {
+		if s.Store.Posts[i].Id.Hex() == id {
+			return &s.Store.Posts[i]
+		}
+	}
+	return nil
+}
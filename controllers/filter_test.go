@@ -0,0 +1,78 @@
+package controllers_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/forum-gamers/nine-tails-fox/config"
+	commentProto "github.com/forum-gamers/nine-tails-fox/generated/comment"
+	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	"github.com/forum-gamers/nine-tails-fox/testutil"
+	"google.golang.org/grpc/codes"
+)
+
+const filterRules = `rules:
+  - name: slurs
+    action: reject
+    words: [cheater]
+  - name: profanity
+    action: mask
+    words: [noob]
+  - name: link-shorteners
+    action: flag
+    domains: [bit.ly]
+`
+
+func newFilteredServer(t *testing.T) *testutil.Server {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte(filterRules), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	return newServer(t, func(cfg *config.Config) {
+		cfg.ContentFilter.RulesFile = path
+	})
+}
+
+func TestContentFilter(t *testing.T) {
+	s := newFilteredServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	_, err := client.CreatePost(ctx, &postProto.PostForm{Text: "what a ch34ter", Privacy: "Public"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	masked := createPost(t, s, ctx, &postProto.PostForm{Text: "you noob", AllowComment: true})
+	if masked.Text != "you ****" {
+		t.Errorf("text = %q, want %q", masked.Text, "you ****")
+	}
+
+	createPost(t, s, ctx, &postProto.PostForm{Text: "free skins at https://bit.ly/x"})
+	if len(s.Store.Reports) != 1 {
+		t.Errorf("reports = %v, want one flagged post", s.Store.Reports)
+	}
+
+	_, err = commentProto.NewCommentServiceClient(s.Conn).CreateComment(ctx, &commentProto.CommentForm{Text: "cheater", PostId: masked.XId})
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestContentFilterDrafts(t *testing.T) {
+	s := newFilteredServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	_, err := client.SaveDraft(ctx, &postProto.DraftForm{Text: "what a cheater", Privacy: "Public"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	draft, err := client.SaveDraft(ctx, &postProto.DraftForm{Text: "you noob", Privacy: "Public"})
+	mustNoError(t, err)
+	if draft.Text != "you ****" {
+		t.Errorf("text = %q, want %q", draft.Text, "you ****")
+	}
+
+	_, err = client.UpdateDraft(ctx, &postProto.UpdateDraftForm{XId: draft.XId, Text: "cheater", Privacy: "Public"})
+	assertCode(t, err, codes.InvalidArgument)
+}
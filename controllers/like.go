@@ -2,16 +2,17 @@ package controllers
 
 import (
 	"context"
-	"sync"
 	"time"
 
 	protobuf "github.com/forum-gamers/nine-tails-fox/generated/like"
+	"github.com/forum-gamers/nine-tails-fox/metrics"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
 	"github.com/forum-gamers/nine-tails-fox/pkg/like"
 	"github.com/forum-gamers/nine-tails-fox/pkg/post"
 	"github.com/forum-gamers/nine-tails-fox/pkg/preference"
+	"github.com/forum-gamers/nine-tails-fox/pkg/relation"
 	"github.com/forum-gamers/nine-tails-fox/pkg/user"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -23,6 +24,9 @@ type LikeService struct {
 	PostRepo              post.PostRepo
 	UserPreferenceRepo    preference.PreferenceRepo
 	UserPreferenceService preference.PreferenceService
+	TrackPreference       bool
+	AuditService          audit.AuditService
+	RelationRepo          relation.RelationRepo
 }
 
 func (s *LikeService) CreateLike(ctx context.Context, in *protobuf.LikeIdPayload) (*protobuf.Like, error) {
@@ -35,41 +39,23 @@ func (s *LikeService) CreateLike(ctx context.Context, in *protobuf.LikeIdPayload
 		return nil, status.Error(codes.InvalidArgument, "Invalid PostId")
 	}
 
-	var post post.Post
-	if err := s.PostRepo.FindById(ctx, postId, &post); err != nil {
+	var postData post.Post
+	if err := s.PostRepo.FindById(ctx, postId, &postData); err != nil {
 		return nil, err
 	}
 
-	var data like.Like
 	userId := s.GetUser(ctx).Id
-	if err := s.LikeRepo.GetLikesByUserIdAndPostId(ctx, postId, userId, &data); err != nil {
-		if e, ok := status.FromError(err); ok && e.Code() != codes.NotFound {
-			return nil, err
-		}
-	}
-
-	if data.Id != primitive.NilObjectID {
-		return nil, status.Error(codes.AlreadyExists, "Conflict")
-	}
-
-	userPreference, err := s.UserPreferenceRepo.FindByUserId(ctx, userId)
-	if err != nil {
+	if err := checkBlocked(ctx, s.RelationRepo, postData.UserId, userId); err != nil {
 		return nil, err
 	}
 
-	session, err := s.PostRepo.GetSession()
-	if err != nil {
-		return nil, status.Error(codes.Unavailable, "Failed get session")
-	}
-	defer session.EndSession(ctx)
-
-	dbCtx := mongo.NewSessionContext(ctx, session)
-	if err := session.StartTransaction(); err != nil {
-		return nil, status.Error(codes.Unavailable, "Failed start DB Operations")
+	var userPreference preference.UserPreference
+	if s.TrackPreference {
+		if userPreference, err = s.UserPreferenceRepo.FindByUserId(ctx, userId); err != nil {
+			return nil, err
+		}
 	}
 
-	var wg sync.WaitGroup
-	errCh := make(chan error)
 	result := like.Like{
 		UserId:    userId,
 		PostId:    postId,
@@ -77,44 +63,25 @@ func (s *LikeService) CreateLike(ctx context.Context, in *protobuf.LikeIdPayload
 		UpdatedAt: time.Now(),
 	}
 
-	handlers := []func(){
-		func() {
-			defer wg.Done()
-			id, err := s.LikeRepo.AddLikes(dbCtx, &result)
-			if err != nil {
-				errCh <- err
-				return
-			}
-			result.Id = id
-			errCh <- nil
-		},
-		func() {
-			defer wg.Done()
-			errCh <- s.UserPreferenceRepo.UpdateTags(dbCtx, userId, s.UserPreferenceService.CreateUserNewTags(dbCtx, userPreference, post.Tags))
-		},
-	}
-
-	for _, handler := range handlers {
-		wg.Add(1)
-		go handler()
-	}
-
-	go func() {
-		wg.Wait()
-		close(errCh)
-	}()
-
-	for err := range errCh {
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		id, err := s.LikeRepo.AddLikes(ctx, &result)
 		if err != nil {
-			session.AbortTransaction(dbCtx)
-			return nil, err
+			return err
 		}
-	}
+		result.Id = id
 
-	if err := session.CommitTransaction(dbCtx); err != nil {
-		session.AbortTransaction(dbCtx)
+		if err := s.PostRepo.IncrementCounter(ctx, postId, post.CountLike, 1); err != nil {
+			return err
+		}
+
+		if !s.TrackPreference {
+			return nil
+		}
+		return s.UserPreferenceRepo.UpdateTags(ctx, userId, s.UserPreferenceService.AdjustTags(ctx, userPreference, postData.Tags, preference.LIKEWEIGHT))
+	}); err != nil {
 		return nil, err
 	}
+	metrics.LikesCreated.Inc()
 
 	return &protobuf.Like{
 		XId:       result.Id.Hex(),
@@ -135,15 +102,32 @@ func (s *LikeService) DeleteLike(ctx context.Context, in *protobuf.LikeIdPayload
 		return nil, status.Error(codes.InvalidArgument, "Invalid ObjectId")
 	}
 
-	userId := s.GetUser(ctx).Id
+	user := s.GetUser(ctx)
 	var data like.Like
-	if err := s.LikeRepo.GetLikesByUserIdAndPostId(ctx, postId, userId, &data); err != nil {
+	if err := s.LikeRepo.GetLikesByUserIdAndPostId(ctx, postId, user.Id, &data); err != nil {
 		return nil, err
 	}
 
-	if err := s.LikeRepo.DeleteLike(ctx, postId, userId); err != nil {
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		deleted, err := s.LikeRepo.DeleteLike(ctx, postId, user.Id)
+		if err != nil {
+			return err
+		}
+
+		// a concurrent request already removed it and took it off the counter
+		if deleted < 1 {
+			return status.Error(codes.NotFound, "Data not found")
+		}
+
+		if err := s.PostRepo.IncrementCounter(ctx, postId, post.CountLike, -1); err != nil {
+			return err
+		}
+
+		return s.AuditService.Record(ctx, user, audit.ActionDeleteLike, audit.TargetLike, data.Id, data, nil)
+	}); err != nil {
 		return nil, err
 	}
+	metrics.LikesDeleted.Inc()
 
 	return &protobuf.Messages{Message: "success"}, nil
 }
@@ -0,0 +1,77 @@
+package controllers_test
+
+import (
+	"testing"
+
+	likeProto "github.com/forum-gamers/nine-tails-fox/generated/like"
+	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	relationProto "github.com/forum-gamers/nine-tails-fox/generated/relation"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCreateLike(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	bobCtx := authAs(t, s, bob)
+	client := likeProto.NewLikeServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	like, err := client.CreateLike(bobCtx, &likeProto.LikeIdPayload{PostId: data.XId})
+	mustNoError(t, err)
+	if like.UserId != bob || like.PostId != data.XId {
+		t.Errorf("CreateLike() = %v", like)
+	}
+
+	result, err := postProto.NewPostServiceClient(s.Conn).FindById(bobCtx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+	if result.CountLike != 1 || !result.IsLiked {
+		t.Errorf("countLike = %d, isLiked = %v, want 1 and true", result.CountLike, result.IsLiked)
+	}
+
+	_, err = client.CreateLike(bobCtx, &likeProto.LikeIdPayload{PostId: data.XId})
+	assertCode(t, err, codes.AlreadyExists)
+
+	_, err = client.CreateLike(bobCtx, &likeProto.LikeIdPayload{})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.CreateLike(bobCtx, &likeProto.LikeIdPayload{PostId: "invalid"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.CreateLike(bobCtx, &likeProto.LikeIdPayload{PostId: missingId()})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = relationProto.NewRelationServiceClient(s.Conn).Block(ctx, &relationProto.UserIdPayload{UserId: "carol"})
+	mustNoError(t, err)
+
+	_, err = client.CreateLike(authAs(t, s, "carol"), &likeProto.LikeIdPayload{PostId: data.XId})
+	assertCode(t, err, codes.PermissionDenied)
+}
+
+func TestDeleteLike(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	bobCtx := authAs(t, s, bob)
+	client := likeProto.NewLikeServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	_, err := client.CreateLike(bobCtx, &likeProto.LikeIdPayload{PostId: data.XId})
+	mustNoError(t, err)
+
+	_, err = client.DeleteLike(ctx, &likeProto.LikeIdPayload{PostId: data.XId})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.DeleteLike(bobCtx, &likeProto.LikeIdPayload{PostId: data.XId})
+	mustNoError(t, err)
+
+	_, err = client.DeleteLike(bobCtx, &likeProto.LikeIdPayload{PostId: data.XId})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.DeleteLike(bobCtx, &likeProto.LikeIdPayload{PostId: "invalid"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	result, err := postProto.NewPostServiceClient(s.Conn).FindById(bobCtx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+	if result.CountLike != 0 || result.IsLiked {
+		t.Errorf("countLike = %d, isLiked = %v, want 0 and false", result.CountLike, result.IsLiked)
+	}
+}
@@ -0,0 +1,393 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/generated"
+	protobuf "github.com/forum-gamers/nine-tails-fox/generated/moderation"
+	"github.com/forum-gamers/nine-tails-fox/metrics"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
+	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
+	"github.com/forum-gamers/nine-tails-fox/pkg/moderation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/spam"
+	"github.com/forum-gamers/nine-tails-fox/pkg/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ModerationService struct {
+	protobuf.UnimplementedModerationServiceServer
+	GetUser           func(ctx context.Context) user.User
+	PostRepo          post.PostRepo
+	CommentRepo       comment.CommentRepo
+	ReportRepo        moderation.ReportRepo
+	ActionRepo        moderation.ActionRepo
+	WarningRepo       moderation.WarningRepo
+	ModerationService moderation.ModerationService
+	AuditService      audit.AuditService
+}
+
+func (s *ModerationService) ReportContent(ctx context.Context, req *protobuf.ReportForm) (*protobuf.Report, error) {
+	if !s.ModerationService.IsValidReason(req.Reason) {
+		return nil, status.Errorf(codes.InvalidArgument, "reason must be one of %s", strings.Join(moderation.Reasons, ","))
+	}
+
+	if len(req.Text) > moderation.MAXREPORTTEXTLENGTH {
+		return nil, status.Errorf(codes.InvalidArgument, "text cannot exceed %d characters", moderation.MAXREPORTTEXTLENGTH)
+	}
+
+	target, _, err := s.findTarget(ctx, req.TargetType, req.TargetId, req.CommentId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	userId := s.GetUser(ctx).Id
+	if target.AuthorId == userId {
+		return nil, status.Error(codes.InvalidArgument, "Cannot report your own content")
+	}
+
+	data := s.ModerationService.CreateReportPayload(target, userId, req.Reason, strings.TrimSpace(req.Text))
+	if err := s.ReportRepo.Create(ctx, &data); err != nil {
+		return nil, err
+	}
+
+	return &protobuf.Report{
+		XId:        data.Id.Hex(),
+		TargetType: data.TargetType,
+		TargetId:   data.TargetId.Hex(),
+		CommentId:  hexOrEmpty(data.CommentId),
+		PostId:     data.PostId.Hex(),
+		ReporterId: data.ReporterId,
+		Reason:     data.Reason,
+		Text:       data.Text,
+		Status:     data.Status,
+		CreatedAt:  data.CreatedAt.String(),
+	}, nil
+}
+
+func (s *ModerationService) GetModerationQueue(ctx context.Context, in *protobuf.QueueParams) (*protobuf.QueueResp, error) {
+	if !s.ModerationService.IsModerator(s.GetUser(ctx)) {
+		return nil, status.Error(codes.PermissionDenied, "Forbidden")
+	}
+
+	if in.Status == "" {
+		in.Status = moderation.Open
+	}
+
+	if in.TargetType != "" && !s.ModerationService.IsValidTarget(in.TargetType) {
+		return nil, status.Error(codes.InvalidArgument, "targetType must be one of Post,Comment,Reply")
+	}
+
+	data, err := s.ReportRepo.GetQueue(ctx, moderation.QueueQuery{
+		Page:       int(in.Page),
+		Limit:      int(in.Limit),
+		Status:     in.Status,
+		TargetType: in.TargetType,
+		Reason:     in.Reason,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &protobuf.QueueResp{
+		TotalData: int64(data[0].TotalData),
+		Page:      in.Page,
+		Limit:     in.Limit,
+		Data:      generated.ParseQueueItemsToProto(data),
+	}, nil
+}
+
+func (s *ModerationService) TakeAction(ctx context.Context, req *protobuf.ActionForm) (*protobuf.Action, error) {
+	moderator := s.GetUser(ctx)
+	if !s.ModerationService.IsModerator(moderator) {
+		return nil, status.Error(codes.PermissionDenied, "Forbidden")
+	}
+
+	if !s.ModerationService.IsValidAction(req.Action) {
+		return nil, status.Error(codes.InvalidArgument, "action must be one of Dismiss,Hide,Delete,Warn,Release")
+	}
+
+	target, before, err := s.findTarget(ctx, req.TargetType, req.TargetId, req.CommentId, req.Action == moderation.ActionRelease)
+	if err != nil {
+		// reports on content that is already gone can still be dismissed
+		if status.Code(err) != codes.NotFound || req.Action != moderation.ActionDismiss {
+			return nil, err
+		}
+		target.TargetType = req.TargetType
+	}
+
+	var data moderation.Action
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.applyAction(ctx, target, req.Action, moderator.Id); err != nil {
+			return err
+		}
+
+		reportStatus := moderation.Resolved
+		if req.Action == moderation.ActionDismiss {
+			reportStatus = moderation.Dismissed
+		}
+
+		resolved, err := s.ReportRepo.Resolve(ctx, target.TargetType, target.TargetId, reportStatus, moderator.Id, time.Now())
+		if err != nil {
+			return err
+		}
+
+		data = s.ModerationService.CreateActionPayload(target, moderator.Id, req.Action, strings.TrimSpace(req.Note), resolved)
+		if err := s.ActionRepo.Create(ctx, &data); err != nil {
+			return err
+		}
+
+		after := before
+		switch {
+		case before == nil:
+		case req.Action == moderation.ActionHide || req.Action == moderation.ActionDelete:
+			after = removedSnapshot(before, req.Action == moderation.ActionHide, moderator.Id, data.CreatedAt)
+		case req.Action == moderation.ActionRelease:
+			after = releasedSnapshot(before)
+		}
+		return s.AuditService.Record(ctx, moderator, audit.ActionModeratePrefix+req.Action, target.TargetType, target.TargetId, before, after)
+	}); err != nil {
+		return nil, err
+	}
+
+	return &protobuf.Action{
+		XId:             data.Id.Hex(),
+		TargetType:      data.TargetType,
+		TargetId:        data.TargetId.Hex(),
+		CommentId:       hexOrEmpty(data.CommentId),
+		PostId:          hexOrEmpty(data.PostId),
+		AuthorId:        data.AuthorId,
+		ModeratorId:     data.ModeratorId,
+		Action:          data.Action,
+		Note:            data.Note,
+		ResolvedReports: data.ResolvedReports,
+		CreatedAt:       data.CreatedAt.String(),
+	}, nil
+}
+
+// GetWarnings returns how often a user was warned, users can read their own
+// count and moderators anyone's.
+func (s *ModerationService) GetWarnings(ctx context.Context, in *protobuf.WarningParams) (*protobuf.Warning, error) {
+	user := s.GetUser(ctx)
+	userId := in.UserId
+	if userId == "" {
+		userId = user.Id
+	}
+
+	if userId != user.Id && !s.ModerationService.IsModerator(user) {
+		return nil, status.Error(codes.PermissionDenied, "Forbidden")
+	}
+
+	data, err := s.WarningRepo.FindByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	lastWarnedAt := ""
+	if !data.LastWarnedAt.IsZero() {
+		lastWarnedAt = data.LastWarnedAt.String()
+	}
+
+	return &protobuf.Warning{
+		UserId:       data.UserId,
+		Count:        int64(data.Count),
+		LastWarnedAt: lastWarnedAt,
+	}, nil
+}
+
+// findTarget loads the reported content and returns where it lives, who
+// wrote it and the content itself. hidden looks up content that is hidden or
+// quarantined instead of visible content. The target ids are always parsed,
+// even when the lookup fails.
+func (s *ModerationService) findTarget(ctx context.Context, targetType, targetId, commentId string, hidden bool) (target moderation.Target, snapshot any, err error) {
+	if !s.ModerationService.IsValidTarget(targetType) {
+		err = status.Error(codes.InvalidArgument, "targetType must be one of Post,Comment,Reply")
+		return
+	}
+
+	if target.TargetId, err = primitive.ObjectIDFromHex(targetId); err != nil {
+		err = status.Error(codes.InvalidArgument, "Invalid targetId")
+		return
+	}
+
+	switch targetType {
+	case moderation.TargetPost:
+		find := s.PostRepo.FindById
+		if hidden {
+			find = s.PostRepo.FindHiddenById
+		}
+
+		var data post.Post
+		if err = find(ctx, target.TargetId, &data); err != nil {
+			return
+		}
+		target.PostId, target.AuthorId, snapshot = data.Id, data.UserId, data
+	case moderation.TargetComment:
+		find := s.CommentRepo.FindById
+		if hidden {
+			find = s.CommentRepo.FindHiddenById
+		}
+
+		var data comment.Comment
+		if err = find(ctx, target.TargetId, &data); err != nil {
+			return
+		}
+		target.PostId, target.AuthorId, snapshot = data.PostId, data.UserId, data
+	case moderation.TargetReply:
+		if target.CommentId, err = primitive.ObjectIDFromHex(commentId); err != nil {
+			err = status.Error(codes.InvalidArgument, "Invalid commentId")
+			return
+		}
+
+		var data comment.Comment
+		if err = s.CommentRepo.FindById(ctx, target.CommentId, &data); err != nil {
+			return
+		}
+
+		find := s.CommentRepo.FindReplyById
+		if hidden {
+			find = s.CommentRepo.FindHiddenReplyById
+		}
+
+		var reply comment.ReplyComment
+		if err = find(ctx, target.CommentId, target.TargetId, &reply); err != nil {
+			return
+		}
+		target.PostId, target.AuthorId, snapshot = data.PostId, reply.UserId, reply
+	}
+	target.TargetType = targetType
+	return
+}
+
+func (s *ModerationService) applyAction(ctx context.Context, target moderation.Target, action, moderatorId string) error {
+	switch action {
+	case moderation.ActionRelease:
+		return s.release(ctx, target)
+	case moderation.ActionWarn:
+		return s.WarningRepo.Increment(ctx, target.AuthorId, time.Now())
+	}
+
+	if action != moderation.ActionHide && action != moderation.ActionDelete {
+		return nil
+	}
+
+	switch target.TargetType {
+	case moderation.TargetPost:
+		if action == moderation.ActionHide {
+			return s.PostRepo.Hide(ctx, target.TargetId, moderatorId)
+		}
+		return s.PostRepo.SoftDelete(ctx, target.TargetId, moderatorId)
+	case moderation.TargetComment:
+		var data comment.Comment
+		if err := s.CommentRepo.FindById(ctx, target.TargetId, &data); err != nil {
+			return err
+		}
+
+		apply := s.CommentRepo.SoftDelete
+		if action == moderation.ActionHide {
+			apply = s.CommentRepo.Hide
+		}
+
+		changed, err := apply(ctx, target.TargetId, moderatorId)
+		if err != nil || changed != 1 {
+			return err
+		}
+		return s.PostRepo.IncrementCounter(ctx, target.PostId, post.CountComment, -visibleThread(data))
+	default:
+		apply := s.CommentRepo.SoftDeleteReply
+		if action == moderation.ActionHide {
+			apply = s.CommentRepo.HideReply
+		}
+
+		changed, err := apply(ctx, target.CommentId, target.TargetId, moderatorId)
+		if err != nil || changed != 1 {
+			return err
+		}
+		return s.PostRepo.IncrementCounter(ctx, target.PostId, post.CountComment, -1)
+	}
+}
+
+// release makes hidden content visible again and puts it back into the
+// comment counter of its post.
+func (s *ModerationService) release(ctx context.Context, target moderation.Target) error {
+	switch target.TargetType {
+	case moderation.TargetPost:
+		return s.PostRepo.Unhide(ctx, target.TargetId)
+	case moderation.TargetComment:
+		var data comment.Comment
+		if err := s.CommentRepo.FindHiddenById(ctx, target.TargetId, &data); err != nil {
+			return err
+		}
+
+		changed, err := s.CommentRepo.Unhide(ctx, target.TargetId)
+		if err != nil || changed != 1 {
+			return err
+		}
+		return s.PostRepo.IncrementCounter(ctx, target.PostId, post.CountComment, visibleThread(data))
+	default:
+		changed, err := s.CommentRepo.UnhideReply(ctx, target.CommentId, target.TargetId)
+		if err != nil || changed != 1 {
+			return err
+		}
+		return s.PostRepo.IncrementCounter(ctx, target.PostId, post.CountComment, 1)
+	}
+}
+
+// visibleThread counts a comment and its replies that are neither deleted nor
+// hidden, which is what the comment counter of its post holds for it. Callers
+// read the comment inside the transaction that changes it.
+func visibleThread(data comment.Comment) int {
+	count := 1
+	for _, reply := range data.Reply {
+		if reply.DeletedAt == nil && reply.HiddenAt == nil {
+			count++
+		}
+	}
+	return count
+}
+
+// checkSpam scores text about to be posted and counts the verdict.
+func checkSpam(ctx context.Context, service spam.SpamService, userId, targetType, text string) (spam.Verdict, error) {
+	verdict, err := service.Check(ctx, userId, text)
+	if err == nil || verdict.Outcome != spam.Allow {
+		metrics.SpamVerdicts.WithLabelValues(targetType, verdict.Outcome).Inc()
+	}
+	return verdict, err
+}
+
+// reviewContent remembers freshly created content for later spam checks and
+// queues it for moderation when the spam check quarantined it or the content
+// filter flagged it.
+func reviewContent(ctx context.Context, spamService spam.SpamService, repo moderation.ReportRepo, service moderation.ModerationService, target moderation.Target, verdict spam.Verdict, flags []string) error {
+	if err := spamService.Record(ctx, target.AuthorId, target.TargetType, verdict); err != nil {
+		return err
+	}
+
+	if verdict.Outcome == spam.Quarantine {
+		data := service.CreateQuarantinePayload(target, verdict.Score, append(verdict.Reasons, flags...))
+		return repo.Create(ctx, &data)
+	}
+	return flagForReview(ctx, repo, service, target, flags)
+}
+
+// flagForReview puts content matched by a flag rule of the content filter
+// into the moderation queue.
+func flagForReview(ctx context.Context, repo moderation.ReportRepo, service moderation.ModerationService, target moderation.Target, rules []string) error {
+	if len(rules) < 1 {
+		return nil
+	}
+
+	data := service.CreateFlagPayload(target, rules)
+	return repo.Create(ctx, &data)
+}
+
+func hexOrEmpty(id primitive.ObjectID) string {
+	if id.IsZero() {
+		return ""
+	}
+	return id.Hex()
+}
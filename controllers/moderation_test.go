@@ -0,0 +1,197 @@
+package controllers_test
+
+import (
+	"context"
+	"testing"
+
+	commentProto "github.com/forum-gamers/nine-tails-fox/generated/comment"
+	moderationProto "github.com/forum-gamers/nine-tails-fox/generated/moderation"
+	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	"google.golang.org/grpc/codes"
+)
+
+func TestReportContent(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := moderationProto.NewModerationServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	report, err := client.ReportContent(authAs(t, s, bob), &moderationProto.ReportForm{TargetType: "Post", TargetId: data.XId, Reason: "Spam", Text: " buy gold "})
+	mustNoError(t, err)
+	if report.ReporterId != bob || report.PostId != data.XId || report.Status != "Open" || report.Text != "buy gold" {
+		t.Errorf("ReportContent() = %v", report)
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		form *moderationProto.ReportForm
+		want codes.Code
+	}{
+		{"own content", ctx, &moderationProto.ReportForm{TargetType: "Post", TargetId: data.XId, Reason: "Spam"}, codes.InvalidArgument},
+		{"invalid reason", authAs(t, s, bob), &moderationProto.ReportForm{TargetType: "Post", TargetId: data.XId, Reason: "Boring"}, codes.InvalidArgument},
+		{"invalid target type", authAs(t, s, bob), &moderationProto.ReportForm{TargetType: "User", TargetId: data.XId, Reason: "Spam"}, codes.InvalidArgument},
+		{"invalid target id", authAs(t, s, bob), &moderationProto.ReportForm{TargetType: "Post", TargetId: "invalid", Reason: "Spam"}, codes.InvalidArgument},
+		{"missing target", authAs(t, s, bob), &moderationProto.ReportForm{TargetType: "Post", TargetId: missingId(), Reason: "Spam"}, codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ReportContent(tt.ctx, tt.form)
+			assertCode(t, err, tt.want)
+		})
+	}
+}
+
+func TestGetModerationQueue(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	adminCtx := authAs(t, s, admin)
+	client := moderationProto.NewModerationServiceClient(s.Conn)
+
+	_, err := client.GetModerationQueue(adminCtx, &moderationProto.QueueParams{})
+	assertCode(t, err, codes.NotFound)
+
+	data := createPost(t, s, ctx, nil)
+	for _, userId := range []string{bob, "carol"} {
+		_, err := client.ReportContent(authAs(t, s, userId), &moderationProto.ReportForm{TargetType: "Post", TargetId: data.XId, Reason: "Spam"})
+		mustNoError(t, err)
+	}
+
+	result, err := client.GetModerationQueue(adminCtx, &moderationProto.QueueParams{})
+	mustNoError(t, err)
+	if result.TotalData != 1 || result.Data[0].ReportCount != 2 || result.Data[0].AuthorId != alice {
+		t.Errorf("GetModerationQueue() = %v", result)
+	}
+
+	_, err = client.GetModerationQueue(adminCtx, &moderationProto.QueueParams{TargetType: "User"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.GetModerationQueue(ctx, &moderationProto.QueueParams{})
+	assertCode(t, err, codes.PermissionDenied)
+
+	moderatorCtx, err := s.AuthContext(context.Background(), "dave", "Moderator")
+	mustNoError(t, err)
+
+	_, err = client.GetModerationQueue(moderatorCtx, &moderationProto.QueueParams{})
+	mustNoError(t, err)
+}
+
+func TestTakeAction(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	adminCtx := authAs(t, s, admin)
+	client := moderationProto.NewModerationServiceClient(s.Conn)
+	posts := postProto.NewPostServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	_, err := client.ReportContent(authAs(t, s, bob), &moderationProto.ReportForm{TargetType: "Post", TargetId: data.XId, Reason: "Spam"})
+	mustNoError(t, err)
+
+	_, err = client.TakeAction(ctx, &moderationProto.ActionForm{TargetType: "Post", TargetId: data.XId, Action: "Hide"})
+	assertCode(t, err, codes.PermissionDenied)
+
+	action, err := client.TakeAction(adminCtx, &moderationProto.ActionForm{TargetType: "Post", TargetId: data.XId, Action: "Hide", Note: "spam"})
+	mustNoError(t, err)
+	if action.ModeratorId != admin || action.AuthorId != alice || action.ResolvedReports != 1 {
+		t.Errorf("TakeAction(Hide) = %v", action)
+	}
+
+	if n := len(s.Store.AuditLog); n != 1 || s.Store.AuditLog[0].Action != "ModerateHide" {
+		t.Errorf("audit log = %v, want one ModerateHide entry", s.Store.AuditLog)
+	}
+
+	_, err = posts.FindById(ctx, &postProto.PostIdPayload{XId: data.XId})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.TakeAction(adminCtx, &moderationProto.ActionForm{TargetType: "Post", TargetId: data.XId, Action: "Release"})
+	mustNoError(t, err)
+
+	_, err = posts.FindById(ctx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+
+	_, err = client.TakeAction(adminCtx, &moderationProto.ActionForm{TargetType: "Post", TargetId: data.XId, Action: "Warn"})
+	mustNoError(t, err)
+
+	warning, err := client.GetWarnings(ctx, &moderationProto.WarningParams{})
+	mustNoError(t, err)
+	if warning.UserId != alice || warning.Count != 1 || warning.LastWarnedAt == "" {
+		t.Errorf("GetWarnings() = %v", warning)
+	}
+
+	t.Run("dismiss missing content", func(t *testing.T) {
+		_, err := client.TakeAction(adminCtx, &moderationProto.ActionForm{TargetType: "Post", TargetId: missingId(), Action: "Dismiss"})
+		mustNoError(t, err)
+	})
+
+	tests := []struct {
+		name string
+		form *moderationProto.ActionForm
+		want codes.Code
+	}{
+		{"invalid action", &moderationProto.ActionForm{TargetType: "Post", TargetId: data.XId, Action: "Ban"}, codes.InvalidArgument},
+		{"invalid target id", &moderationProto.ActionForm{TargetType: "Post", TargetId: "invalid", Action: "Hide"}, codes.InvalidArgument},
+		{"invalid comment id", &moderationProto.ActionForm{TargetType: "Reply", TargetId: missingId(), CommentId: "invalid", Action: "Hide"}, codes.InvalidArgument},
+		{"missing target", &moderationProto.ActionForm{TargetType: "Post", TargetId: missingId(), Action: "Hide"}, codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.TakeAction(adminCtx, tt.form)
+			assertCode(t, err, tt.want)
+		})
+	}
+}
+
+func TestGetWarnings(t *testing.T) {
+	s := newServer(t)
+	client := moderationProto.NewModerationServiceClient(s.Conn)
+
+	data, err := client.GetWarnings(authAs(t, s, alice), &moderationProto.WarningParams{})
+	mustNoError(t, err)
+	if data.UserId != alice || data.Count != 0 || data.LastWarnedAt != "" {
+		t.Errorf("GetWarnings() = %v", data)
+	}
+
+	_, err = client.GetWarnings(authAs(t, s, bob), &moderationProto.WarningParams{UserId: alice})
+	assertCode(t, err, codes.PermissionDenied)
+
+	data, err = client.GetWarnings(authAs(t, s, admin), &moderationProto.WarningParams{UserId: alice})
+	mustNoError(t, err)
+	if data.UserId != alice {
+		t.Errorf("GetWarnings() = %v", data)
+	}
+}
+
+func TestModerateCommentCounter(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	adminCtx := authAs(t, s, admin)
+	client := moderationProto.NewModerationServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	comment, err := commentProto.NewCommentServiceClient(s.Conn).CreateComment(authAs(t, s, bob), &commentProto.CommentForm{Text: "first", PostId: data.XId})
+	mustNoError(t, err)
+
+	tests := []struct {
+		action string
+		code   codes.Code
+		want   int64
+	}{
+		{"Hide", codes.OK, 0},
+		{"Hide", codes.NotFound, 0},
+		{"Release", codes.OK, 1},
+		{"Release", codes.NotFound, 1},
+	}
+
+	for _, tt := range tests {
+		_, err := client.TakeAction(adminCtx, &moderationProto.ActionForm{TargetType: "Comment", TargetId: comment.XId, Action: tt.action})
+		assertCode(t, err, tt.code)
+
+		result, err := postProto.NewPostServiceClient(s.Conn).FindById(ctx, &postProto.PostIdPayload{XId: data.XId})
+		mustNoError(t, err)
+		if result.CountComment != tt.want {
+			t.Errorf("countComment after %s = %d, want %d", tt.action, result.CountComment, tt.want)
+		}
+	}
+}
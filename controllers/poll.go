@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/generated"
+	protobuf "github.com/forum-gamers/nine-tails-fox/generated/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/poll"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *PostService) VotePoll(ctx context.Context, req *protobuf.VotePollPayload) (*protobuf.Poll, error) {
+	if req.PostId == "" {
+		return nil, status.Error(codes.InvalidArgument, "postId is required")
+	}
+
+	postId, err := primitive.ObjectIDFromHex(req.PostId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid PostId")
+	}
+
+	var data post.Post
+	if err := s.PostRepo.FindById(ctx, postId, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Poll == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Post has no poll")
+	}
+
+	if poll.IsClosed(data.Poll, time.Now()) {
+		return nil, status.Error(codes.FailedPrecondition, "Poll is closed")
+	}
+
+	options, err := parseVoteOptions(data.Poll, req.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	vote := s.PollService.CreateVotePayload(postId, s.GetUser(ctx).Id, options)
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.PollRepo.CreateVote(ctx, &vote); err != nil {
+			return err
+		}
+
+		return s.PostRepo.AddPollVotes(ctx, postId, options)
+	}); err != nil {
+		return nil, err
+	}
+
+	result := *data.Poll
+	result.Options = append([]post.PollOption(nil), data.Poll.Options...)
+	result.TotalVotes++
+	for _, option := range options {
+		result.Options[option].Count++
+	}
+	result.MyVotes = options
+	return generated.ParsePollToProto(&result), nil
+}
+
+func (s *PostService) parsePoll(form *protobuf.PollForm) (*post.Poll, error) {
+	if form == nil {
+		return nil, nil
+	}
+
+	if len(form.Options) < poll.MINOPTIONS || len(form.Options) > poll.MAXOPTIONS {
+		return nil, status.Errorf(codes.InvalidArgument, "Poll must have between %d and %d options", poll.MINOPTIONS, poll.MAXOPTIONS)
+	}
+
+	options := make([]string, 0, len(form.Options))
+	seen := map[string]bool{}
+	for _, option := range form.Options {
+		option = strings.TrimSpace(option)
+		switch true {
+		case option == "":
+			return nil, status.Error(codes.InvalidArgument, "Poll option cannot be empty")
+		case len(option) > poll.MAXOPTIONLENGTH:
+			return nil, status.Errorf(codes.InvalidArgument, "Poll option cannot exceed %d characters", poll.MAXOPTIONLENGTH)
+		case seen[strings.ToLower(option)]:
+			return nil, status.Errorf(codes.InvalidArgument, "Duplicate poll option %q", option)
+		}
+		seen[strings.ToLower(option)] = true
+		options = append(options, option)
+	}
+
+	var closesAt *time.Time
+	if form.ClosesAt != "" {
+		at, err := time.Parse(time.RFC3339, form.ClosesAt)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "closesAt must be an RFC3339 timestamp")
+		}
+
+		if !at.After(time.Now()) {
+			return nil, status.Error(codes.InvalidArgument, "closesAt must be in the future")
+		}
+		closesAt = &at
+	}
+
+	return s.PollService.CreatePayload(options, form.MultipleChoice, form.HideResults, closesAt), nil
+}
+
+func parseVoteOptions(data *post.Poll, in []int32) ([]int, error) {
+	if len(in) < 1 {
+		return nil, status.Error(codes.InvalidArgument, "options is required")
+	}
+
+	if !data.MultipleChoice && len(in) > 1 {
+		return nil, status.Error(codes.InvalidArgument, "Poll only allows a single choice")
+	}
+
+	options := make([]int, 0, len(in))
+	seen := map[int32]bool{}
+	for _, option := range in {
+		if option < 0 || int(option) >= len(data.Options) {
+			return nil, status.Errorf(codes.InvalidArgument, "Invalid option %d", option)
+		}
+
+		if seen[option] {
+			return nil, status.Errorf(codes.InvalidArgument, "Duplicate option %d", option)
+		}
+		seen[option] = true
+		options = append(options, int(option))
+	}
+	return options, nil
+}
@@ -2,82 +2,154 @@ package controllers
 
 import (
 	"context"
-	"sync"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/forum-gamers/nine-tails-fox/filter"
 	"github.com/forum-gamers/nine-tails-fox/generated"
 	protobuf "github.com/forum-gamers/nine-tails-fox/generated/post"
 	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	"github.com/forum-gamers/nine-tails-fox/metrics"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
 	"github.com/forum-gamers/nine-tails-fox/pkg/base"
 	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
 	"github.com/forum-gamers/nine-tails-fox/pkg/like"
+	"github.com/forum-gamers/nine-tails-fox/pkg/lock"
+	"github.com/forum-gamers/nine-tails-fox/pkg/moderation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/poll"
 	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/preference"
+	"github.com/forum-gamers/nine-tails-fox/pkg/preview"
+	"github.com/forum-gamers/nine-tails-fox/pkg/relation"
 	"github.com/forum-gamers/nine-tails-fox/pkg/share"
+	"github.com/forum-gamers/nine-tails-fox/pkg/spam"
 	"github.com/forum-gamers/nine-tails-fox/pkg/user"
 	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type PostService struct {
 	protobuf.UnimplementedPostServiceServer
-	GetUser     func(ctx context.Context) user.User
-	PostRepo    post.PostRepo
-	PostService post.PostService
-	LikeRepo    like.LikeRepo
-	CommentRepo comment.CommentRepo
-	ShareRepo   share.ShareRepo
+	GetUser           func(ctx context.Context) user.User
+	PostRepo          post.PostRepo
+	PostService       post.PostService
+	LikeRepo          like.LikeRepo
+	CommentRepo       comment.CommentRepo
+	ShareRepo         share.ShareRepo
+	PollRepo          poll.PollRepo
+	PollService       poll.PollService
+	TrashRetention    time.Duration
+	MaxPins           int
+	AuditService      audit.AuditService
+	ContentFilter     filter.ContentFilter
+	ReportRepo        moderation.ReportRepo
+	ModerationService moderation.ModerationService
+	SpamService       spam.SpamService
+	MediaRules        post.MediaRules
+	RelationRepo      relation.RelationRepo
+	PreferenceRepo    preference.PreferenceRepo
+	PreviewService    preview.PreviewService
+	LockRepo          lock.LockRepo
 }
 
 func (s *PostService) CreatePost(ctx context.Context, req *protobuf.PostForm) (*protobuf.Post, error) {
-	tags := []string{}
-	if len(req.Text) > 0 {
-		tags = s.PostService.GetPostTags(req.Text)
-	}
-
-	postMedias := make([]post.Media, 0)
-	if len(req.Files) > 0 {
-		for _, file := range req.Files {
-			postMedias = append(postMedias, post.Media{
-				Url:  file.Url,
-				Type: file.ContentType,
-				Id:   file.FileId,
-			})
-		}
+	postMedias, err := s.parseFiles(req.Files)
+	if err != nil {
+		return nil, err
 	}
 
 	if !h.IsValidPrivacy(req.Privacy) {
 		return nil, status.Error(codes.InvalidArgument, "Privacy must be on of Public,Private,Friend Only")
 	}
 
-	userId := s.GetUser(ctx).Id
-	post := s.PostService.CreatePostPayload(userId, req.Text, req.Privacy, req.AllowComment, postMedias, tags)
+	poll, err := s.parsePoll(req.Poll)
+	if err != nil {
+		return nil, err
+	}
 
-	s.PostRepo.Create(context.Background(), &post)
-	resultMedia := make([]*protobuf.Media, 0)
-	if len(post.Media) > 0 {
-		for _, media := range post.Media {
-			resultMedia = append(resultMedia, &protobuf.Media{
-				Id:   media.Id,
-				Url:  media.Url,
-				Type: media.Type,
-			})
-		}
+	post := s.PostService.CreatePostPayload(s.GetUser(ctx).Id, req.Text, req.Privacy, req.AllowComment, postMedias, []string{})
+	post.Poll = poll
+	if err := s.publish(ctx, &post, func(ctx context.Context) error {
+		return s.PostRepo.Create(ctx, &post)
+	}); err != nil {
+		return nil, err
 	}
 
 	return &protobuf.Post{
 		XId:          post.Id.Hex(),
 		UserId:       post.UserId,
 		Text:         post.Text,
-		Media:        resultMedia,
+		Media:        generated.ParseMediaToProto(post.Media),
 		AllowComment: post.AllowComment,
 		CreatedAt:    post.CreatedAt.String(),
 		UpdatedAt:    post.UpdatedAt.String(),
 		Tags:         post.Tags,
 		Privacy:      post.Privacy,
+		Poll:         generated.ParsePollToProto(post.Poll),
+		Previews:     generated.ParseLinkPreviewsToProto(post.Previews),
 	}, nil
 }
 
+// publish runs what every post goes through when it becomes visible, whether
+// it was created directly, published from a draft or by the scheduler. The
+// text and poll options pass the content filter again so rule changes since a
+// draft was saved apply, the author is checked for spam and links in the text
+// are previewed. store writes data in the same transaction that records the
+// spam fingerprint and queues the post for moderation.
+func (s *PostService) publish(ctx context.Context, data *post.Post, store func(ctx context.Context) error) error {
+	checked, err := s.ContentFilter.Check(data.Text)
+	if err != nil {
+		return err
+	}
+
+	data.Text = checked.Text
+	data.Tags = []string{}
+	if len(data.Text) > 0 {
+		data.Tags = s.PostService.GetPostTags(data.Text)
+	}
+
+	flags := checked.Flags
+	if data.Poll != nil {
+		for i, option := range data.Poll.Options {
+			result, err := s.ContentFilter.Check(option.Text)
+			if err != nil {
+				return err
+			}
+			data.Poll.Options[i].Text = result.Text
+			flags = append(flags, result.Flags...)
+		}
+	}
+
+	verdict, err := checkSpam(ctx, s.SpamService, data.UserId, moderation.TargetPost, data.Text)
+	if err != nil {
+		return err
+	}
+
+	data.Previews = s.PreviewService.Resolve(ctx, s.PreviewService.ExtractUrls(data.Text))
+	if verdict.Outcome == spam.Quarantine {
+		data.HiddenAt, data.HiddenBy = &data.CreatedAt, moderation.SYSTEMREPORTER
+	}
+
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := store(ctx); err != nil {
+			return err
+		}
+
+		return reviewContent(ctx, s.SpamService, s.ReportRepo, s.ModerationService, moderation.Target{
+			TargetType: moderation.TargetPost,
+			TargetId:   data.Id,
+			PostId:     data.Id,
+			AuthorId:   data.UserId,
+		}, verdict, flags)
+	}); err != nil {
+		return err
+	}
+	metrics.PostsCreated.Inc()
+	return nil
+}
+
 func (s *PostService) DeletePost(ctx context.Context, req *protobuf.PostIdPayload) (*protobuf.ListIdsResp, error) {
 	if req.XId == "" {
 		return nil, status.Error(codes.InvalidArgument, "_id is required")
@@ -90,7 +162,13 @@ func (s *PostService) DeletePost(ctx context.Context, req *protobuf.PostIdPayloa
 
 	var data post.Post
 	if err := s.PostRepo.FindById(ctx, postId, &data); err != nil {
-		return nil, err
+		if status.Code(err) != codes.NotFound {
+			return nil, err
+		}
+
+		if err := s.PostRepo.FindDraftById(ctx, postId, &data); err != nil {
+			return nil, err
+		}
 	}
 
 	user := s.GetUser(ctx)
@@ -98,77 +176,104 @@ func (s *PostService) DeletePost(ctx context.Context, req *protobuf.PostIdPayloa
 		return nil, status.Error(codes.Unauthenticated, "Forbidden")
 	}
 
-	resp := []string{}
-	if len(data.Media) > 0 {
-		for _, media := range data.Media {
-			resp = append(resp, media.Id)
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.PostRepo.SoftDelete(ctx, data.Id, user.Id); err != nil {
+			return err
 		}
+
+		return s.AuditService.Record(ctx, user, audit.ActionDeletePost, audit.TargetPost, data.Id, data, removedSnapshot(data, false, user.Id, time.Now()))
+	}); err != nil {
+		return nil, err
 	}
-	session, err := s.PostRepo.GetSession()
+	metrics.PostsDeleted.Inc()
+	return &protobuf.ListIdsResp{Datas: []string{}}, nil
+}
+
+func (s *PostService) RestorePost(ctx context.Context, req *protobuf.PostIdPayload) (*protobuf.Messages, error) {
+	if req.XId == "" {
+		return nil, status.Error(codes.InvalidArgument, "_id is required")
+	}
+
+	postId, err := primitive.ObjectIDFromHex(req.XId)
 	if err != nil {
-		return nil, status.Error(codes.Unavailable, "Failed get session")
-	}
-	defer session.EndSession(ctx)
-
-	dbCtx := mongo.NewSessionContext(ctx, session)
-	if err := session.StartTransaction(); err != nil {
-		return nil, status.Error(codes.Unavailable, "Failed start DB Operations")
-	}
-
-	var wg sync.WaitGroup
-	errCh := make(chan error)
-	handlers := []func(){
-		func() {
-			defer wg.Done()
-			errCh <- s.LikeRepo.DeletePostLikes(dbCtx, data.Id)
-		},
-		func() {
-			defer wg.Done()
-			errCh <- s.PostRepo.DeleteOne(dbCtx, data.Id)
-		},
-		func() {
-			defer wg.Done()
-			errCh <- s.ShareRepo.DeleteMany(ctx, data.Id)
-		},
-		func() {
-			defer wg.Done()
-			errCh <- s.CommentRepo.DeleteMany(ctx, data.Id)
-		},
-	}
-
-	for _, handler := range handlers {
-		wg.Add(1)
-		go handler()
-	}
-
-	go func() {
-		wg.Wait()
-		close(errCh)
-	}()
-
-	for err := range errCh {
-		if err != nil {
-			session.AbortTransaction(dbCtx)
-			return nil, err
+		return nil, status.Error(codes.InvalidArgument, "Invalid ObjectId")
+	}
+
+	var data post.Post
+	if err := s.PostRepo.FindDeletedById(ctx, postId, &data); err != nil {
+		return nil, err
+	}
+
+	user := s.GetUser(ctx)
+	if data.UserId != user.Id || data.DeletedBy != user.Id {
+		return nil, status.Error(codes.PermissionDenied, "Forbidden")
+	}
+
+	if time.Since(*data.DeletedAt) > s.TrashRetention {
+		return nil, status.Error(codes.FailedPrecondition, "Retention window has passed")
+	}
+
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.PostRepo.Restore(ctx, postId); err != nil {
+			return err
 		}
+
+		restored := data
+		restored.DeletedAt, restored.DeletedBy = nil, ""
+		return s.AuditService.Record(ctx, user, audit.ActionRestorePost, audit.TargetPost, postId, data, restored)
+	}); err != nil {
+		return nil, err
 	}
 
-	if err := session.CommitTransaction(dbCtx); err != nil {
-		session.AbortTransaction(dbCtx)
+	return &protobuf.Messages{Message: "success"}, nil
+}
+
+func (s *PostService) ListTrash(ctx context.Context, in *protobuf.Pagination) (*protobuf.PostRespWithMetadata, error) {
+	UUID := s.GetUser(ctx).Id
+
+	data, err := s.PostRepo.GetTrash(ctx, UUID, time.Now().Add(-s.TrashRetention), in)
+	if err != nil {
 		return nil, err
 	}
 
-	return &protobuf.ListIdsResp{Datas: resp}, nil
+	if err := s.PollService.ApplyViewer(ctx, UUID, data); err != nil {
+		return nil, err
+	}
+
+	return &protobuf.PostRespWithMetadata{
+		TotalData: int64(data[0].TotalData),
+		Page:      in.Page,
+		Limit:     in.Limit,
+		Data:      generated.ParsePostRespToProto(data),
+	}, nil
 }
 
 func (s *PostService) GetPublicContent(ctx context.Context, in *protobuf.GetPostParams) (*protobuf.PostRespWithMetadata, error) {
 	UUID := s.GetUser(ctx).Id
 
-	data, err := s.PostRepo.GetPublicContent(ctx, UUID, in)
+	excluded, err := s.RelationRepo.FindHiddenUserIds(ctx, UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	userPreference, err := s.PreferenceRepo.FindByUserId(ctx, UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, tag := range in.Tags {
+		in.Tags[i] = strings.ToLower(strings.TrimSpace(tag))
+	}
+
+	data, err := s.PostRepo.GetPublicContent(ctx, UUID, excluded, userPreference.MutedTags, in)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.PollService.ApplyViewer(ctx, UUID, data); err != nil {
+		return nil, err
+	}
+
 	return &protobuf.PostRespWithMetadata{
 		TotalData: int64(data[0].TotalData),
 		Page:      in.Page,
@@ -185,6 +290,10 @@ func (s *PostService) GetUserPost(ctx context.Context, in *protobuf.Pagination)
 		return nil, err
 	}
 
+	if err := s.PollService.ApplyViewer(ctx, UUID, data); err != nil {
+		return nil, err
+	}
+
 	return &protobuf.PostRespWithMetadata{
 		TotalData: int64(data[0].TotalData),
 		Page:      in.Page,
@@ -203,6 +312,10 @@ func (s *PostService) GetLikedPost(ctx context.Context, in *protobuf.Pagination)
 		return nil, err
 	}
 
+	if err := s.PollService.ApplyViewer(ctx, UUID, data); err != nil {
+		return nil, err
+	}
+
 	return &protobuf.PostRespWithMetadata{
 		TotalData: int64(data[0].TotalData),
 		Page:      in.Page,
@@ -219,6 +332,10 @@ func (s *PostService) GetUserMedia(ctx context.Context, in *protobuf.Pagination)
 		return nil, err
 	}
 
+	if err := s.PollService.ApplyViewer(ctx, UUID, data); err != nil {
+		return nil, err
+	}
+
 	return &protobuf.PostRespWithMetadata{
 		TotalData: int64(data[0].TotalData),
 		Page:      in.Page,
@@ -233,6 +350,10 @@ func (s *PostService) GetUserPostById(ctx context.Context, in *protobuf.Paginati
 		return nil, err
 	}
 
+	if err := s.PollService.ApplyViewer(ctx, s.GetUser(ctx).Id, data); err != nil {
+		return nil, err
+	}
+
 	return &protobuf.PostRespWithMetadata{
 		TotalData: int64(data[0].TotalData),
 		Page:      in.Page,
@@ -247,6 +368,10 @@ func (s *PostService) GetMediaByUserId(ctx context.Context, in *protobuf.Paginat
 		return nil, err
 	}
 
+	if err := s.PollService.ApplyViewer(ctx, s.GetUser(ctx).Id, data); err != nil {
+		return nil, err
+	}
+
 	return &protobuf.PostRespWithMetadata{
 		TotalData: int64(data[0].TotalData),
 		Page:      in.Page,
@@ -255,12 +380,82 @@ func (s *PostService) GetMediaByUserId(ctx context.Context, in *protobuf.Paginat
 	}, nil
 }
 
+func (s *PostService) GetMediaGallery(ctx context.Context, in *protobuf.MediaGalleryParams) (*protobuf.MediaGalleryResp, error) {
+	switch in.Kind {
+	case "", post.MediaImage, post.MediaVideo, post.MediaGif:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "kind must be one of Image,Video,Gif")
+	}
+
+	from, err := parseOptionalTime(in.From)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "from must be an RFC3339 timestamp")
+	}
+
+	to, err := parseOptionalTime(in.To)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "to must be an RFC3339 timestamp")
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+
+	query := post.MediaQuery{
+		Page:   int(in.Page),
+		Limit:  int(in.Limit),
+		UserId: in.UserId,
+		Kind:   in.Kind,
+		From:   from,
+		To:     to,
+	}
+
+	if in.PostId != "" {
+		if query.PostId, err = primitive.ObjectIDFromHex(in.PostId); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "Invalid PostId")
+		}
+	}
+
+	viewerId := s.GetUser(ctx).Id
+	if query.UserId == "" {
+		query.UserId = viewerId
+	}
+
+	if query.UserId != viewerId {
+		excluded, err := s.RelationRepo.FindHiddenUserIds(ctx, viewerId)
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.Contains(excluded, query.UserId) {
+			return nil, status.Error(codes.NotFound, "data not found")
+		}
+		query.PublicOnly = true
+	}
+
+	data, err := s.PostRepo.GetMediaItems(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &protobuf.MediaGalleryResp{
+		TotalData: int64(data[0].TotalData),
+		Page:      in.Page,
+		Limit:     in.Limit,
+		Data:      generated.ParseMediaItemsToProto(data),
+	}, nil
+}
+
 func (s *PostService) GetUserLikedPost(ctx context.Context, in *protobuf.PaginationWithUserId) (*protobuf.PostRespWithMetadata, error) {
 	data, err := s.LikeRepo.FindUserLikedPost(ctx, in.UserId, base.Pagination{Page: uint32(in.Page), Limit: uint32(in.Limit)})
 	if err != nil {
 		return nil, err
 	}
 
+	if err := s.PollService.ApplyViewer(ctx, s.GetUser(ctx).Id, data); err != nil {
+		return nil, err
+	}
+
 	return &protobuf.PostRespWithMetadata{
 		TotalData: int64(data[0].TotalData),
 		Page:      in.Page,
@@ -292,19 +487,15 @@ func (s *PostService) FindById(ctx context.Context, in *protobuf.PostIdPayload)
 		return nil, err
 	}
 
-	var medias []*protobuf.Media
-	for _, media := range data.Media {
-		medias = append(medias, &protobuf.Media{
-			Url:  media.Url,
-			Id:   media.Id,
-			Type: media.Type,
-		})
+	if err := s.PollService.ApplyViewer(ctx, s.GetUser(ctx).Id, []post.PostResponse{data}); err != nil {
+		return nil, err
 	}
+
 	return &protobuf.PostResponse{
 		XId:          data.Id.Hex(),
 		UserId:       data.UserId,
 		Text:         data.Text,
-		Media:        medias,
+		Media:        generated.ParseMediaToProto(data.Media),
 		AllowComment: data.AllowComment,
 		CreatedAt:    data.CreatedAt.String(),
 		UpdatedAt:    data.UpdatedAt.String(),
@@ -315,5 +506,79 @@ func (s *PostService) FindById(ctx context.Context, in *protobuf.PostIdPayload)
 		IsShared:     data.IsShared,
 		Tags:         data.Tags,
 		TotalData:    int64(data.TotalData),
+		Poll:         generated.ParsePollToProto(data.Poll),
+		Previews:     generated.ParseLinkPreviewsToProto(data.Previews),
 	}, nil
 }
+
+func (s *PostService) PinPost(ctx context.Context, req *protobuf.PostIdPayload) (*protobuf.Messages, error) {
+	data, err := s.findOwnPost(ctx, req.XId)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.PinnedAt != nil {
+		return nil, status.Error(codes.AlreadyExists, "Post already pinned")
+	}
+
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		// two pins counting at once would both see room left
+		if err := s.LockRepo.Acquire(ctx, lock.Pin, data.UserId); err != nil {
+			return err
+		}
+
+		count, err := s.PostRepo.CountPinned(ctx, data.UserId)
+		if err != nil {
+			return err
+		}
+
+		if count >= int64(s.MaxPins) {
+			return status.Errorf(codes.FailedPrecondition, "Cannot pin more than %d posts", s.MaxPins)
+		}
+
+		return s.PostRepo.Pin(ctx, data.Id, time.Now())
+	}); err != nil {
+		return nil, err
+	}
+
+	return &protobuf.Messages{Message: "success"}, nil
+}
+
+func (s *PostService) UnpinPost(ctx context.Context, req *protobuf.PostIdPayload) (*protobuf.Messages, error) {
+	data, err := s.findOwnPost(ctx, req.XId)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.PinnedAt == nil {
+		return nil, status.Error(codes.FailedPrecondition, "Post is not pinned")
+	}
+
+	if err := s.PostRepo.Unpin(ctx, data.Id); err != nil {
+		return nil, err
+	}
+
+	return &protobuf.Messages{Message: "success"}, nil
+}
+
+func (s *PostService) findOwnPost(ctx context.Context, id string) (data post.Post, err error) {
+	if id == "" {
+		err = status.Error(codes.InvalidArgument, "_id is required")
+		return
+	}
+
+	postId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, "Invalid ObjectId")
+		return
+	}
+
+	if err = s.PostRepo.FindById(ctx, postId, &data); err != nil {
+		return
+	}
+
+	if data.UserId != s.GetUser(ctx).Id {
+		err = status.Error(codes.PermissionDenied, "Forbidden")
+	}
+	return
+}
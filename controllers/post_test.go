@@ -0,0 +1,459 @@
+package controllers_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	likeProto "github.com/forum-gamers/nine-tails-fox/generated/like"
+	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	relationProto "github.com/forum-gamers/nine-tails-fox/generated/relation"
+	"google.golang.org/grpc/codes"
+)
+
+var image = &postProto.FileHeader{
+	ContentType: "image/png",
+	Url:         "https://cdn.example.com/a.png",
+	FileId:      "file-1",
+	Width:       100,
+	Height:      100,
+}
+
+func TestCreatePost(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	data := createPost(t, s, ctx, &postProto.PostForm{
+		Text:  "Hello World",
+		Files: []*postProto.FileHeader{image},
+		Poll:  &postProto.PollForm{Options: []string{"yes", "no"}},
+	})
+	if data.UserId != alice || len(data.Media) != 1 || len(data.Poll.GetOptions()) != 2 {
+		t.Errorf("CreatePost() = %v", data)
+	}
+
+	if len(data.Tags) != 2 || data.Tags[0] != "hello" || data.Tags[1] != "world" {
+		t.Errorf("tags = %v, want [hello world]", data.Tags)
+	}
+
+	tests := []struct {
+		name string
+		form *postProto.PostForm
+	}{
+		{"invalid privacy", &postProto.PostForm{Text: "text", Privacy: "Secret"}},
+		{"invalid media", &postProto.PostForm{Text: "text", Privacy: "Public", Files: []*postProto.FileHeader{{ContentType: "image/png", Url: "ftp://example.com/a.png"}}}},
+		{"single poll option", &postProto.PostForm{Text: "text", Privacy: "Public", Poll: &postProto.PollForm{Options: []string{"yes"}}}},
+		{"duplicate poll option", &postProto.PostForm{Text: "text", Privacy: "Public", Poll: &postProto.PollForm{Options: []string{"yes", "YES"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreatePost(ctx, tt.form)
+			assertCode(t, err, codes.InvalidArgument)
+		})
+	}
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		_, err := client.CreatePost(context.Background(), &postProto.PostForm{Text: "text", Privacy: "Public"})
+		assertCode(t, err, codes.Unauthenticated)
+	})
+}
+
+func TestDeletePost(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	_, err := client.DeletePost(authAs(t, s, bob), &postProto.PostIdPayload{XId: data.XId})
+	assertCode(t, err, codes.Unauthenticated)
+
+	deleted, err := client.DeletePost(ctx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+	if len(deleted.Datas) != 0 {
+		t.Errorf("DeletePost() = %v, want no media ids", deleted.Datas)
+	}
+
+	_, err = client.FindById(ctx, &postProto.PostIdPayload{XId: data.XId})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.DeletePost(ctx, &postProto.PostIdPayload{XId: data.XId})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.DeletePost(ctx, &postProto.PostIdPayload{})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.DeletePost(ctx, &postProto.PostIdPayload{XId: "invalid"})
+	assertCode(t, err, codes.InvalidArgument)
+	if len(s.Store.AuditLog) != 1 || s.Store.AuditLog[0].Action != "DeletePost" {
+		t.Errorf("audit log = %v, want one DeletePost entry", s.Store.AuditLog)
+	}
+}
+
+func TestRestorePostAndListTrash(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	_, err := client.ListTrash(ctx, &postProto.Pagination{})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.DeletePost(ctx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+
+	trash, err := client.ListTrash(ctx, &postProto.Pagination{})
+	mustNoError(t, err)
+	if trash.TotalData != 1 || trash.Data[0].XId != data.XId {
+		t.Errorf("ListTrash() = %v", trash)
+	}
+
+	_, err = client.RestorePost(authAs(t, s, bob), &postProto.PostIdPayload{XId: data.XId})
+	assertCode(t, err, codes.PermissionDenied)
+
+	_, err = client.RestorePost(ctx, &postProto.PostIdPayload{XId: "invalid"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.RestorePost(ctx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+
+	_, err = client.FindById(ctx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+
+	_, err = client.RestorePost(ctx, &postProto.PostIdPayload{XId: data.XId})
+	assertCode(t, err, codes.NotFound)
+}
+
+func TestPurgeQueuesMediaCleanup(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	data := createPost(t, s, ctx, &postProto.PostForm{Files: []*postProto.FileHeader{image}})
+
+	_, err := postProto.NewPostServiceClient(s.Conn).DeletePost(ctx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+
+	deletedAt := time.Now().Add(-s.Config.Trash.Retention - time.Hour)
+	findStored(s, data.XId).DeletedAt = &deletedAt
+
+	mustNoError(t, s.Purger.Run(context.Background()))
+
+	if findStored(s, data.XId) != nil {
+		t.Errorf("post %s is still stored after the purge", data.XId)
+	}
+
+	if len(s.Store.Cleanups) != 1 || s.Store.Cleanups[0].PostId.Hex() != data.XId || len(s.Store.Cleanups[0].MediaIds) != 1 {
+		t.Errorf("cleanups = %v, want the media of %s", s.Store.Cleanups, data.XId)
+	}
+}
+
+func TestFindById(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	result, err := client.FindById(authAs(t, s, bob), &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+	if result.XId != data.XId || result.Text != data.Text {
+		t.Errorf("FindById() = %v, want %v", result, data)
+	}
+
+	_, err = client.FindById(ctx, &postProto.PostIdPayload{XId: missingId()})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.FindById(ctx, &postProto.PostIdPayload{})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.FindById(ctx, &postProto.PostIdPayload{XId: "invalid"})
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestPostFeeds(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	bobCtx := authAs(t, s, bob)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	_, err := client.GetUserPost(ctx, &postProto.Pagination{})
+	assertCode(t, err, codes.NotFound)
+
+	first := createPost(t, s, ctx, nil)
+	second := createPost(t, s, ctx, nil)
+
+	_, err = likeProto.NewLikeServiceClient(s.Conn).CreateLike(bobCtx, &likeProto.LikeIdPayload{PostId: first.XId})
+	mustNoError(t, err)
+
+	tests := []struct {
+		name string
+		call func() (*postProto.PostRespWithMetadata, error)
+		want []string
+	}{
+		{"GetPublicContent", func() (*postProto.PostRespWithMetadata, error) {
+			return client.GetPublicContent(bobCtx, &postProto.GetPostParams{})
+		}, []string{second.XId, first.XId}},
+		{"GetUserPost", func() (*postProto.PostRespWithMetadata, error) {
+			return client.GetUserPost(ctx, &postProto.Pagination{})
+		}, []string{second.XId, first.XId}},
+		{"GetUserPostById", func() (*postProto.PostRespWithMetadata, error) {
+			return client.GetUserPostById(bobCtx, &postProto.PaginationWithUserId{UserId: alice})
+		}, []string{second.XId, first.XId}},
+		{"GetLikedPost", func() (*postProto.PostRespWithMetadata, error) {
+			return client.GetLikedPost(bobCtx, &postProto.Pagination{})
+		}, []string{first.XId}},
+		{"GetUserLikedPost", func() (*postProto.PostRespWithMetadata, error) {
+			return client.GetUserLikedPost(ctx, &postProto.PaginationWithUserId{UserId: bob})
+		}, []string{first.XId}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.call()
+			mustNoError(t, err)
+			assertPosts(t, result, tt.want)
+		})
+	}
+
+	t.Run("GetPublicContent hides blocked users", func(t *testing.T) {
+		_, err := relationProto.NewRelationServiceClient(s.Conn).Block(ctx, &relationProto.UserIdPayload{UserId: bob})
+		mustNoError(t, err)
+
+		_, err = client.GetPublicContent(bobCtx, &postProto.GetPostParams{})
+		assertCode(t, err, codes.NotFound)
+	})
+
+	t.Run("GetUserLikedPost without likes", func(t *testing.T) {
+		_, err := client.GetUserLikedPost(bobCtx, &postProto.PaginationWithUserId{UserId: alice})
+		assertCode(t, err, codes.NotFound)
+	})
+}
+
+func TestMediaFeeds(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	_, err := client.GetUserMedia(ctx, &postProto.Pagination{})
+	assertCode(t, err, codes.NotFound)
+
+	createPost(t, s, ctx, nil)
+	data := createPost(t, s, ctx, &postProto.PostForm{Files: []*postProto.FileHeader{image}})
+
+	result, err := client.GetUserMedia(ctx, &postProto.Pagination{})
+	mustNoError(t, err)
+	assertPosts(t, result, []string{data.XId})
+
+	result, err = client.GetMediaByUserId(authAs(t, s, bob), &postProto.PaginationWithUserId{UserId: alice})
+	mustNoError(t, err)
+	assertPosts(t, result, []string{data.XId})
+}
+
+func TestGetTopTags(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	_, err := client.GetTopTags(ctx, &postProto.Pagination{})
+	assertCode(t, err, codes.NotFound)
+
+	createPost(t, s, ctx, &postProto.PostForm{Text: "valorant ranked"})
+	createPost(t, s, ctx, &postProto.PostForm{Text: "Valorant tips"})
+
+	result, err := client.GetTopTags(ctx, &postProto.Pagination{})
+	mustNoError(t, err)
+	if len(result.Datas) < 1 || result.Datas[0].XId != "valorant" || result.Datas[0].Count != 2 {
+		t.Errorf("GetTopTags() = %v, want valorant first with 2 posts", result.Datas)
+	}
+}
+
+func TestPinPost(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	var ids []string
+	for i := 0; i <= s.Config.Post.MaxPins; i++ {
+		ids = append(ids, createPost(t, s, ctx, nil).XId)
+	}
+
+	_, err := client.PinPost(authAs(t, s, bob), &postProto.PostIdPayload{XId: ids[0]})
+	assertCode(t, err, codes.PermissionDenied)
+
+	_, err = client.UnpinPost(ctx, &postProto.PostIdPayload{XId: ids[0]})
+	assertCode(t, err, codes.FailedPrecondition)
+
+	for _, id := range ids[:s.Config.Post.MaxPins] {
+		_, err := client.PinPost(ctx, &postProto.PostIdPayload{XId: id})
+		mustNoError(t, err)
+	}
+
+	_, err = client.PinPost(ctx, &postProto.PostIdPayload{XId: ids[0]})
+	assertCode(t, err, codes.AlreadyExists)
+
+	_, err = client.PinPost(ctx, &postProto.PostIdPayload{XId: ids[s.Config.Post.MaxPins]})
+	assertCode(t, err, codes.FailedPrecondition)
+
+	result, err := client.GetUserPost(ctx, &postProto.Pagination{})
+	mustNoError(t, err)
+	if last := result.Data[len(result.Data)-1].XId; last != ids[s.Config.Post.MaxPins] {
+		t.Errorf("GetUserPost() ends with %s, want the newest but unpinned post %s", last, ids[s.Config.Post.MaxPins])
+	}
+
+	_, err = client.UnpinPost(ctx, &postProto.PostIdPayload{XId: ids[0]})
+	mustNoError(t, err)
+
+	_, err = client.PinPost(ctx, &postProto.PostIdPayload{XId: ids[s.Config.Post.MaxPins]})
+	mustNoError(t, err)
+
+	_, err = client.PinPost(ctx, &postProto.PostIdPayload{XId: missingId()})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.UnpinPost(ctx, &postProto.PostIdPayload{})
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestPinnedPostTrashedAndRestored(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+	maxPins := s.Config.Post.MaxPins
+
+	var ids []string
+	for i := 0; i <= maxPins; i++ {
+		ids = append(ids, createPost(t, s, ctx, nil).XId)
+	}
+
+	for _, id := range ids[:maxPins] {
+		_, err := client.PinPost(ctx, &postProto.PostIdPayload{XId: id})
+		mustNoError(t, err)
+	}
+
+	_, err := client.DeletePost(ctx, &postProto.PostIdPayload{XId: ids[0]})
+	mustNoError(t, err)
+
+	_, err = client.PinPost(ctx, &postProto.PostIdPayload{XId: ids[maxPins]})
+	mustNoError(t, err)
+
+	_, err = client.RestorePost(ctx, &postProto.PostIdPayload{XId: ids[0]})
+	mustNoError(t, err)
+
+	pinned := 0
+	for _, id := range ids {
+		if findStored(s, id).PinnedAt != nil {
+			pinned++
+		}
+	}
+
+	if pinned != maxPins || findStored(s, ids[0]).PinnedAt != nil {
+		t.Errorf("%d pinned posts after the restore, want %d without the restored one", pinned, maxPins)
+	}
+
+	_, err = client.PinPost(ctx, &postProto.PostIdPayload{XId: ids[0]})
+	assertCode(t, err, codes.FailedPrecondition)
+}
+
+func TestVotePoll(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	bobCtx := authAs(t, s, bob)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	data := createPost(t, s, ctx, &postProto.PostForm{Poll: &postProto.PollForm{Options: []string{"yes", "no", "maybe"}}})
+	plain := createPost(t, s, ctx, nil)
+
+	result, err := client.VotePoll(bobCtx, &postProto.VotePollPayload{PostId: data.XId, Options: []int32{1}})
+	mustNoError(t, err)
+	if result.TotalVotes != 1 || result.Options[1].Count != 1 || len(result.MyVotes) != 1 {
+		t.Errorf("VotePoll() = %v", result)
+	}
+
+	_, err = client.VotePoll(bobCtx, &postProto.VotePollPayload{PostId: data.XId, Options: []int32{0}})
+	assertCode(t, err, codes.AlreadyExists)
+
+	tests := []struct {
+		name    string
+		payload *postProto.VotePollPayload
+		want    codes.Code
+	}{
+		{"missing post id", &postProto.VotePollPayload{Options: []int32{0}}, codes.InvalidArgument},
+		{"no options", &postProto.VotePollPayload{PostId: data.XId}, codes.InvalidArgument},
+		{"out of range", &postProto.VotePollPayload{PostId: data.XId, Options: []int32{3}}, codes.InvalidArgument},
+		{"multiple on single choice", &postProto.VotePollPayload{PostId: data.XId, Options: []int32{0, 1}}, codes.InvalidArgument},
+		{"post without poll", &postProto.VotePollPayload{PostId: plain.XId, Options: []int32{0}}, codes.FailedPrecondition},
+		{"missing post", &postProto.VotePollPayload{PostId: missingId(), Options: []int32{0}}, codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.VotePoll(ctx, tt.payload)
+			assertCode(t, err, tt.want)
+		})
+	}
+}
+
+func TestGetMediaGallery(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	bobCtx := authAs(t, s, bob)
+	client := postProto.NewPostServiceClient(s.Conn)
+
+	video := &postProto.FileHeader{ContentType: "Video/MP4", Url: "https://cdn.example.com/a.mp4", Duration: 3}
+	data := createPost(t, s, ctx, &postProto.PostForm{Files: []*postProto.FileHeader{image, video}})
+
+	result, err := client.GetMediaGallery(ctx, &postProto.MediaGalleryParams{})
+	mustNoError(t, err)
+	if result.TotalData != 2 || result.Data[0].PostId != data.XId {
+		t.Errorf("GetMediaGallery() = %v", result)
+	}
+
+	result, err = client.GetMediaGallery(bobCtx, &postProto.MediaGalleryParams{UserId: alice, Kind: "Video"})
+	mustNoError(t, err)
+	if result.TotalData != 1 || result.Data[0].Media.Type != "video/mp4" {
+		t.Errorf("GetMediaGallery(Video) = %v", result)
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name   string
+		params *postProto.MediaGalleryParams
+	}{
+		{"invalid kind", &postProto.MediaGalleryParams{Kind: "Audio"}},
+		{"invalid from", &postProto.MediaGalleryParams{From: "yesterday"}},
+		{"invalid post id", &postProto.MediaGalleryParams{PostId: "invalid"}},
+		{"from after to", &postProto.MediaGalleryParams{
+			From: now.Format(time.RFC3339),
+			To:   now.Add(-time.Hour).Format(time.RFC3339),
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.GetMediaGallery(ctx, tt.params)
+			assertCode(t, err, codes.InvalidArgument)
+		})
+	}
+
+	t.Run("blocked viewer", func(t *testing.T) {
+		_, err := relationProto.NewRelationServiceClient(s.Conn).Block(ctx, &relationProto.UserIdPayload{UserId: bob})
+		mustNoError(t, err)
+
+		_, err = client.GetMediaGallery(bobCtx, &postProto.MediaGalleryParams{UserId: alice})
+		assertCode(t, err, codes.NotFound)
+	})
+}
+
+// assertPosts checks that a feed holds exactly the wanted posts, in any order.
+func assertPosts(t *testing.T, result *postProto.PostRespWithMetadata, want []string) {
+	t.Helper()
+
+	if int(result.TotalData) != len(want) || len(result.Data) != len(want) {
+		t.Fatalf("got %d of %d posts, want %d", len(result.Data), result.TotalData, len(want))
+	}
+
+	for _, data := range result.Data {
+		if !slices.Contains(want, data.XId) {
+			t.Errorf("unexpected post %s, want %v", data.XId, want)
+		}
+	}
+}
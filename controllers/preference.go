@@ -0,0 +1,195 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/generated"
+	protobuf "github.com/forum-gamers/nine-tails-fox/generated/preference"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/preference"
+	"github.com/forum-gamers/nine-tails-fox/pkg/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type PreferenceService struct {
+	protobuf.UnimplementedPreferenceServiceServer
+	GetUser               func(ctx context.Context) user.User
+	PostRepo              post.PostRepo
+	UserPreferenceRepo    preference.PreferenceRepo
+	UserPreferenceService preference.PreferenceService
+	AuditService          audit.AuditService
+}
+
+func (s *PreferenceService) GetMyPreferences(ctx context.Context, in *protobuf.NoArguments) (*protobuf.Preference, error) {
+	return s.current(ctx, s.GetUser(ctx).Id)
+}
+
+func (s *PreferenceService) AddTag(ctx context.Context, in *protobuf.TagPayload) (*protobuf.Preference, error) {
+	return s.updateTags(ctx, in, "", func(ctx context.Context, data preference.UserPreference, tag string) []preference.TagPreference {
+		return s.UserPreferenceService.AdjustTags(ctx, data, []string{tag}, preference.LIKEWEIGHT)
+	})
+}
+
+func (s *PreferenceService) RemoveTag(ctx context.Context, in *protobuf.TagPayload) (*protobuf.Preference, error) {
+	return s.updateTags(ctx, in, audit.ActionRemoveTag, func(ctx context.Context, data preference.UserPreference, tag string) []preference.TagPreference {
+		return s.UserPreferenceService.RemoveTag(data, tag)
+	})
+}
+
+func (s *PreferenceService) MuteTag(ctx context.Context, in *protobuf.TagPayload) (*protobuf.Preference, error) {
+	return s.updateMutedTags(ctx, in, s.UserPreferenceRepo.MuteTag)
+}
+
+func (s *PreferenceService) UnmuteTag(ctx context.Context, in *protobuf.TagPayload) (*protobuf.Preference, error) {
+	return s.updateMutedTags(ctx, in, s.UserPreferenceRepo.UnmuteTag)
+}
+
+func (s *PreferenceService) NotInterested(ctx context.Context, in *protobuf.PostIdPayload) (*protobuf.Preference, error) {
+	if in.PostId == "" {
+		return nil, status.Error(codes.InvalidArgument, "postId is required")
+	}
+
+	postId, err := primitive.ObjectIDFromHex(in.PostId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "Invalid PostId")
+	}
+
+	var postData post.Post
+	if err := s.PostRepo.FindById(ctx, postId, &postData); err != nil {
+		return nil, err
+	}
+
+	userId := s.GetUser(ctx).Id
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		data, err := s.UserPreferenceRepo.FindByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+		return s.UserPreferenceRepo.UpdateTags(ctx, userId, s.UserPreferenceService.AdjustTags(ctx, data, postData.Tags, preference.NOTINTERESTEDWEIGHT))
+	}); err != nil {
+		return nil, err
+	}
+	return s.current(ctx, userId)
+}
+
+func (s *PreferenceService) ResetPreferences(ctx context.Context, in *protobuf.NoArguments) (*protobuf.Messages, error) {
+	userId := s.GetUser(ctx).Id
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		data, err := s.UserPreferenceRepo.FindByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		if err := s.UserPreferenceRepo.Reset(ctx, userId); err != nil {
+			return err
+		}
+
+		reset := data
+		reset.Tags, reset.MutedTags = []preference.TagPreference{}, []string{}
+		return s.record(ctx, audit.ActionResetPreference, data, reset)
+	}); err != nil {
+		return nil, err
+	}
+	return &protobuf.Messages{Message: "success"}, nil
+}
+
+func (s *PreferenceService) SuggestTags(ctx context.Context, in *protobuf.SuggestParams) (*protobuf.TagSuggestions, error) {
+	data, err := s.UserPreferenceRepo.FindByUserId(ctx, s.GetUser(ctx).Id)
+	if err != nil {
+		return nil, err
+	}
+
+	seeds, excluded := s.UserPreferenceService.SuggestionSeeds(data)
+	if len(seeds) < 1 {
+		return nil, status.Error(codes.NotFound, "data not found")
+	}
+
+	tags, err := s.PostRepo.GetRelatedTags(ctx, seeds, excluded, time.Now().Add(-preference.SUGGESTWINDOW), int(in.Limit))
+	if err != nil {
+		return nil, err
+	}
+	return &protobuf.TagSuggestions{Data: generated.ParseTagSuggestionsToProto(tags)}, nil
+}
+
+// updateTags stores the tags returned by update, destructive updates pass an
+// audit action so the previous preference is recorded. The preference is read
+// inside the transaction so concurrent updates conflict instead of one of them
+// being lost.
+func (s *PreferenceService) updateTags(ctx context.Context, in *protobuf.TagPayload, action string, update func(ctx context.Context, data preference.UserPreference, tag string) []preference.TagPreference) (*protobuf.Preference, error) {
+	tag, err := parseTag(in)
+	if err != nil {
+		return nil, err
+	}
+
+	userId := s.GetUser(ctx).Id
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		data, err := s.UserPreferenceRepo.FindByUserId(ctx, userId)
+		if err != nil {
+			return err
+		}
+
+		tags := update(ctx, data, tag)
+		if err := s.UserPreferenceRepo.UpdateTags(ctx, userId, tags); err != nil {
+			return err
+		}
+
+		if action == "" {
+			return nil
+		}
+
+		updated := data
+		updated.Tags = tags
+		return s.record(ctx, action, data, updated)
+	}); err != nil {
+		return nil, err
+	}
+	return s.current(ctx, userId)
+}
+
+func (s *PreferenceService) updateMutedTags(ctx context.Context, in *protobuf.TagPayload, update func(ctx context.Context, userId, tag string) error) (*protobuf.Preference, error) {
+	tag, err := parseTag(in)
+	if err != nil {
+		return nil, err
+	}
+
+	userId := s.GetUser(ctx).Id
+	if err := update(ctx, userId, tag); err != nil {
+		return nil, err
+	}
+	return s.current(ctx, userId)
+}
+
+// record audits a change to a stored preference, users without one have
+// nothing to lose and are not recorded.
+func (s *PreferenceService) record(ctx context.Context, action string, before, after preference.UserPreference) error {
+	if before.Id.IsZero() {
+		return nil
+	}
+	return s.AuditService.Record(ctx, s.GetUser(ctx), action, audit.TargetPreference, before.Id, before, after)
+}
+
+// current returns the stored preference with tag weights decayed to now.
+func (s *PreferenceService) current(ctx context.Context, userId string) (*protobuf.Preference, error) {
+	data, err := s.UserPreferenceRepo.FindByUserId(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Tags = s.UserPreferenceService.Ranked(data)
+	return generated.ParsePreferenceToProto(data), nil
+}
+
+// parseTag lower cases the tag the same way post tags are, so muted tags
+// match them exactly.
+func parseTag(in *protobuf.TagPayload) (string, error) {
+	tag := strings.ToLower(strings.TrimSpace(in.Tag))
+	if tag == "" {
+		return "", status.Error(codes.InvalidArgument, "tag is required")
+	}
+	return tag, nil
+}
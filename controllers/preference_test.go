@@ -0,0 +1,166 @@
+package controllers_test
+
+import (
+	"testing"
+
+	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	preferenceProto "github.com/forum-gamers/nine-tails-fox/generated/preference"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
+	"google.golang.org/grpc/codes"
+)
+
+func TestPreferenceTags(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := preferenceProto.NewPreferenceServiceClient(s.Conn)
+
+	data, err := client.GetMyPreferences(ctx, &preferenceProto.NoArguments{})
+	mustNoError(t, err)
+	if data.UserId != alice || len(data.Tags) != 0 || len(data.MutedTags) != 0 {
+		t.Errorf("GetMyPreferences() = %v", data)
+	}
+
+	data, err = client.AddTag(ctx, &preferenceProto.TagPayload{Tag: " valorant "})
+	mustNoError(t, err)
+	if len(data.Tags) != 1 || data.Tags[0].Value != "valorant" || data.Tags[0].Weight <= 0 {
+		t.Errorf("AddTag() = %v", data)
+	}
+
+	data, err = client.RemoveTag(ctx, &preferenceProto.TagPayload{Tag: "Valorant"})
+	mustNoError(t, err)
+	if len(data.Tags) != 0 {
+		t.Errorf("RemoveTag() = %v", data)
+	}
+
+	if n := len(s.Store.AuditLog); n != 1 || s.Store.AuditLog[0].Action != audit.ActionRemoveTag {
+		t.Errorf("audit log = %v, want one RemoveTag entry", s.Store.AuditLog)
+	}
+
+	for _, call := range []func(in *preferenceProto.TagPayload) (*preferenceProto.Preference, error){
+		func(in *preferenceProto.TagPayload) (*preferenceProto.Preference, error) {
+			return client.AddTag(ctx, in)
+		},
+		func(in *preferenceProto.TagPayload) (*preferenceProto.Preference, error) {
+			return client.RemoveTag(ctx, in)
+		},
+		func(in *preferenceProto.TagPayload) (*preferenceProto.Preference, error) {
+			return client.MuteTag(ctx, in)
+		},
+		func(in *preferenceProto.TagPayload) (*preferenceProto.Preference, error) {
+			return client.UnmuteTag(ctx, in)
+		},
+	} {
+		_, err := call(&preferenceProto.TagPayload{Tag: "  "})
+		assertCode(t, err, codes.InvalidArgument)
+	}
+}
+
+func TestMuteTag(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := preferenceProto.NewPreferenceServiceClient(s.Conn)
+	posts := postProto.NewPostServiceClient(s.Conn)
+
+	createPost(t, s, authAs(t, s, bob), &postProto.PostForm{Text: "Minecraft build"})
+
+	data, err := client.MuteTag(ctx, &preferenceProto.TagPayload{Tag: "MINECRAFT"})
+	mustNoError(t, err)
+	if len(data.MutedTags) != 1 || data.MutedTags[0] != "minecraft" {
+		t.Errorf("MuteTag() = %v", data)
+	}
+
+	_, err = posts.GetPublicContent(ctx, &postProto.GetPostParams{})
+	assertCode(t, err, codes.NotFound)
+
+	data, err = client.UnmuteTag(ctx, &preferenceProto.TagPayload{Tag: "Minecraft"})
+	mustNoError(t, err)
+	if len(data.MutedTags) != 0 {
+		t.Errorf("UnmuteTag() = %v", data)
+	}
+
+	_, err = posts.GetPublicContent(ctx, &postProto.GetPostParams{})
+	mustNoError(t, err)
+}
+
+func TestNotInterested(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := preferenceProto.NewPreferenceServiceClient(s.Conn)
+	data := createPost(t, s, authAs(t, s, bob), &postProto.PostForm{Text: "fortnite"})
+
+	result, err := client.NotInterested(ctx, &preferenceProto.PostIdPayload{PostId: data.XId})
+	mustNoError(t, err)
+	if len(result.Tags) != 1 || result.Tags[0].Value != "fortnite" || result.Tags[0].Weight >= 0 {
+		t.Errorf("NotInterested() = %v", result)
+	}
+
+	// posts stored before tags were lower cased
+	legacy := createPost(t, s, authAs(t, s, bob), &postProto.PostForm{Text: "apex legends"})
+	findStored(s, legacy.XId).Tags = []string{"Fortnite", "Apex"}
+
+	result, err = client.NotInterested(ctx, &preferenceProto.PostIdPayload{PostId: legacy.XId})
+	mustNoError(t, err)
+	if len(result.Tags) != 2 || result.Tags[0].Value != "apex" || result.Tags[1].Value != "fortnite" {
+		t.Errorf("NotInterested(legacy) = %v, want apex and fortnite merged into the stored tag", result)
+	}
+
+	_, err = client.NotInterested(ctx, &preferenceProto.PostIdPayload{})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.NotInterested(ctx, &preferenceProto.PostIdPayload{PostId: "invalid"})
+	assertCode(t, err, codes.InvalidArgument)
+
+	_, err = client.NotInterested(ctx, &preferenceProto.PostIdPayload{PostId: missingId()})
+	assertCode(t, err, codes.NotFound)
+}
+
+func TestResetPreferences(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := preferenceProto.NewPreferenceServiceClient(s.Conn)
+
+	_, err := client.ResetPreferences(ctx, &preferenceProto.NoArguments{})
+	mustNoError(t, err)
+	if len(s.Store.AuditLog) != 0 {
+		t.Errorf("audit log = %v, want no entry without a stored preference", s.Store.AuditLog)
+	}
+
+	_, err = client.AddTag(ctx, &preferenceProto.TagPayload{Tag: "valorant"})
+	mustNoError(t, err)
+
+	_, err = client.MuteTag(ctx, &preferenceProto.TagPayload{Tag: "minecraft"})
+	mustNoError(t, err)
+
+	_, err = client.ResetPreferences(ctx, &preferenceProto.NoArguments{})
+	mustNoError(t, err)
+
+	data, err := client.GetMyPreferences(ctx, &preferenceProto.NoArguments{})
+	mustNoError(t, err)
+	if len(data.Tags) != 0 || len(data.MutedTags) != 0 {
+		t.Errorf("GetMyPreferences() after reset = %v", data)
+	}
+
+	if n := len(s.Store.AuditLog); n != 1 || s.Store.AuditLog[0].Action != audit.ActionResetPreference {
+		t.Errorf("audit log = %v, want one ResetPreference entry", s.Store.AuditLog)
+	}
+}
+
+func TestSuggestTags(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := preferenceProto.NewPreferenceServiceClient(s.Conn)
+
+	_, err := client.SuggestTags(ctx, &preferenceProto.SuggestParams{Limit: 5})
+	assertCode(t, err, codes.NotFound)
+
+	createPost(t, s, authAs(t, s, bob), &postProto.PostForm{Text: "valorant esports"})
+
+	_, err = client.AddTag(ctx, &preferenceProto.TagPayload{Tag: "valorant"})
+	mustNoError(t, err)
+
+	result, err := client.SuggestTags(ctx, &preferenceProto.SuggestParams{Limit: 5})
+	mustNoError(t, err)
+	if len(result.Data) != 1 || result.Data[0].Tag != "esports" {
+		t.Errorf("SuggestTags() = %v, want esports", result.Data)
+	}
+}
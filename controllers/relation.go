@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"context"
+
+	protobuf "github.com/forum-gamers/nine-tails-fox/generated/relation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/relation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type RelationService struct {
+	protobuf.UnimplementedRelationServiceServer
+	GetUser         func(ctx context.Context) user.User
+	PostRepo        post.PostRepo
+	RelationRepo    relation.RelationRepo
+	RelationService relation.RelationService
+	AuditService    audit.AuditService
+}
+
+func (s *RelationService) Block(ctx context.Context, in *protobuf.UserIdPayload) (*protobuf.Relation, error) {
+	return s.create(ctx, in, relation.Block)
+}
+
+func (s *RelationService) Unblock(ctx context.Context, in *protobuf.UserIdPayload) (*protobuf.Messages, error) {
+	return s.delete(ctx, in, relation.Block)
+}
+
+func (s *RelationService) Mute(ctx context.Context, in *protobuf.UserIdPayload) (*protobuf.Relation, error) {
+	return s.create(ctx, in, relation.Mute)
+}
+
+func (s *RelationService) Unmute(ctx context.Context, in *protobuf.UserIdPayload) (*protobuf.Messages, error) {
+	return s.delete(ctx, in, relation.Mute)
+}
+
+func (s *RelationService) create(ctx context.Context, in *protobuf.UserIdPayload, relationType string) (*protobuf.Relation, error) {
+	userId, err := s.targetOf(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	data := s.RelationService.CreatePayload(userId, in.UserId, relationType)
+	if err := s.RelationRepo.Create(ctx, &data); err != nil {
+		return nil, err
+	}
+
+	return &protobuf.Relation{
+		XId:       data.Id.Hex(),
+		UserId:    data.UserId,
+		TargetId:  data.TargetId,
+		Type:      data.Type,
+		CreatedAt: data.CreatedAt.Local().String(),
+	}, nil
+}
+
+func (s *RelationService) delete(ctx context.Context, in *protobuf.UserIdPayload, relationType string) (*protobuf.Messages, error) {
+	userId, err := s.targetOf(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.RelationRepo.FindOne(ctx, userId, in.UserId, relationType)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.RelationRepo.Delete(ctx, userId, in.UserId, relationType); err != nil {
+			return err
+		}
+
+		return s.AuditService.Record(ctx, s.GetUser(ctx), audit.ActionDeleteRelation, audit.TargetRelation, data.Id, data, nil)
+	}); err != nil {
+		return nil, err
+	}
+	return &protobuf.Messages{Message: "success"}, nil
+}
+
+// targetOf validates the payload and returns the caller's id.
+func (s *RelationService) targetOf(ctx context.Context, in *protobuf.UserIdPayload) (string, error) {
+	if in.UserId == "" {
+		return "", status.Error(codes.InvalidArgument, "userId is required")
+	}
+
+	userId := s.GetUser(ctx).Id
+	if userId == in.UserId {
+		return "", status.Error(codes.InvalidArgument, "Cannot target yourself")
+	}
+	return userId, nil
+}
+
+// checkBlocked refuses interactions with content owned by a user who blocked the caller.
+func checkBlocked(ctx context.Context, repo relation.RelationRepo, ownerId, userId string) error {
+	if ownerId == userId {
+		return nil
+	}
+
+	blocked, err := repo.IsBlocked(ctx, ownerId, userId)
+	if err != nil {
+		return err
+	}
+
+	if blocked {
+		return status.Error(codes.PermissionDenied, "You have been blocked by this user")
+	}
+	return nil
+}
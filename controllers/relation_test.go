@@ -0,0 +1,66 @@
+package controllers_test
+
+import (
+	"testing"
+
+	relationProto "github.com/forum-gamers/nine-tails-fox/generated/relation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/relation"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRelations(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := relationProto.NewRelationServiceClient(s.Conn)
+
+	tests := []struct {
+		name   string
+		create func(in *relationProto.UserIdPayload) (*relationProto.Relation, error)
+		delete func(in *relationProto.UserIdPayload) (*relationProto.Messages, error)
+		kind   string
+	}{
+		{"Block", func(in *relationProto.UserIdPayload) (*relationProto.Relation, error) {
+			return client.Block(ctx, in)
+		}, func(in *relationProto.UserIdPayload) (*relationProto.Messages, error) {
+			return client.Unblock(ctx, in)
+		}, relation.Block},
+		{"Mute", func(in *relationProto.UserIdPayload) (*relationProto.Relation, error) {
+			return client.Mute(ctx, in)
+		}, func(in *relationProto.UserIdPayload) (*relationProto.Messages, error) {
+			return client.Unmute(ctx, in)
+		}, relation.Mute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.create(&relationProto.UserIdPayload{UserId: bob})
+			mustNoError(t, err)
+			if data.UserId != alice || data.TargetId != bob || data.Type != tt.kind {
+				t.Errorf("%s() = %v", tt.name, data)
+			}
+
+			_, err = tt.create(&relationProto.UserIdPayload{UserId: bob})
+			assertCode(t, err, codes.AlreadyExists)
+
+			_, err = tt.create(&relationProto.UserIdPayload{})
+			assertCode(t, err, codes.InvalidArgument)
+
+			_, err = tt.create(&relationProto.UserIdPayload{UserId: alice})
+			assertCode(t, err, codes.InvalidArgument)
+
+			before := len(s.Store.AuditLog)
+			_, err = tt.delete(&relationProto.UserIdPayload{UserId: bob})
+			mustNoError(t, err)
+
+			if len(s.Store.AuditLog) != before+1 || s.Store.AuditLog[before].TargetId.Hex() != data.XId {
+				t.Errorf("audit log = %v, want an entry for %s", s.Store.AuditLog, data.XId)
+			}
+
+			_, err = tt.delete(&relationProto.UserIdPayload{UserId: bob})
+			assertCode(t, err, codes.NotFound)
+
+			_, err = tt.delete(&relationProto.UserIdPayload{UserId: alice})
+			assertCode(t, err, codes.InvalidArgument)
+		})
+	}
+}
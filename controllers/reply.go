@@ -2,10 +2,18 @@ package controllers
 
 import (
 	"context"
+	"time"
 
+	"github.com/forum-gamers/nine-tails-fox/filter"
 	protobuf "github.com/forum-gamers/nine-tails-fox/generated/reply"
+	"github.com/forum-gamers/nine-tails-fox/metrics"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
 	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
+	"github.com/forum-gamers/nine-tails-fox/pkg/moderation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/relation"
 	"github.com/forum-gamers/nine-tails-fox/pkg/reply"
+	"github.com/forum-gamers/nine-tails-fox/pkg/spam"
 	"github.com/forum-gamers/nine-tails-fox/pkg/user"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/grpc/codes"
@@ -14,10 +22,17 @@ import (
 
 type ReplyService struct {
 	protobuf.UnimplementedReplyServiceServer
-	GetUser        func(ctx context.Context) user.User
-	CommentRepo    comment.CommentRepo
-	CommentService comment.CommentService
-	ReplyService   reply.ReplyService
+	GetUser           func(ctx context.Context) user.User
+	PostRepo          post.PostRepo
+	CommentRepo       comment.CommentRepo
+	CommentService    comment.CommentService
+	ReplyService      reply.ReplyService
+	AuditService      audit.AuditService
+	ContentFilter     filter.ContentFilter
+	ReportRepo        moderation.ReportRepo
+	ModerationService moderation.ModerationService
+	SpamService       spam.SpamService
+	RelationRepo      relation.RelationRepo
 }
 
 func (s *ReplyService) CreateReply(ctx context.Context, req *protobuf.CommentForm) (*protobuf.Reply, error) {
@@ -35,11 +50,58 @@ func (s *ReplyService) CreateReply(ctx context.Context, req *protobuf.CommentFor
 		return nil, err
 	}
 
-	replyPayload := s.ReplyService.CreatePayload(req.Text, s.GetUser(ctx).Id)
-	if err := s.CommentRepo.CreateReply(ctx, commentId, &replyPayload); err != nil {
+	var postData post.Post
+	if err := s.PostRepo.FindById(ctx, commentData.PostId, &postData); err != nil {
 		return nil, err
 	}
 
+	userId := s.GetUser(ctx).Id
+	for _, ownerId := range []string{postData.UserId, commentData.UserId} {
+		if err := checkBlocked(ctx, s.RelationRepo, ownerId, userId); err != nil {
+			return nil, err
+		}
+	}
+
+	checked, err := s.ContentFilter.Check(req.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	verdict, err := checkSpam(ctx, s.SpamService, userId, moderation.TargetReply, checked.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	replyPayload := s.ReplyService.CreatePayload(checked.Text, userId)
+	quarantined := verdict.Outcome == spam.Quarantine
+	if quarantined {
+		replyPayload.HiddenAt, replyPayload.HiddenBy = &replyPayload.CreatedAt, moderation.SYSTEMREPORTER
+	}
+
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.CommentRepo.CreateReply(ctx, commentId, &replyPayload); err != nil {
+			return err
+		}
+
+		// quarantined replies are counted once a moderator releases them
+		if !quarantined {
+			if err := s.PostRepo.IncrementCounter(ctx, commentData.PostId, post.CountComment, 1); err != nil {
+				return err
+			}
+		}
+
+		return reviewContent(ctx, s.SpamService, s.ReportRepo, s.ModerationService, moderation.Target{
+			TargetType: moderation.TargetReply,
+			TargetId:   replyPayload.Id,
+			CommentId:  commentId,
+			PostId:     commentData.PostId,
+			AuthorId:   replyPayload.UserId,
+		}, verdict, checked.Flags)
+	}); err != nil {
+		return nil, err
+	}
+	metrics.RepliesCreated.Inc()
+
 	return &protobuf.Reply{
 		XId:       replyPayload.Id.Hex(),
 		Text:      replyPayload.Text,
@@ -68,17 +130,35 @@ func (s *ReplyService) DeleteReply(ctx context.Context, req *protobuf.DeleteRepl
 		return nil, status.Error(codes.InvalidArgument, "invalid commentId")
 	}
 
+	var commentData comment.Comment
+	if err := s.CommentRepo.FindById(ctx, commentId, &commentData); err != nil {
+		return nil, err
+	}
+
 	var data comment.ReplyComment
 	if err := s.CommentRepo.FindReplyById(ctx, commentId, replyId, &data); err != nil {
 		return nil, err
 	}
 
 	user := s.GetUser(ctx)
-	if data.UserId != user.Id || user.AccountType != "Admin" {
+	if data.UserId != user.Id && user.AccountType != "Admin" {
 		return nil, status.Error(codes.PermissionDenied, "Forbidden")
 	}
 
-	if err := s.CommentRepo.DeleteOneReply(ctx, commentId, replyId); err != nil {
+	if err := s.PostRepo.WithTransaction(ctx, func(ctx context.Context) error {
+		deleted, err := s.CommentRepo.SoftDeleteReply(ctx, commentId, replyId, user.Id)
+		if err != nil {
+			return err
+		}
+
+		if deleted == 1 {
+			if err := s.PostRepo.IncrementCounter(ctx, commentData.PostId, post.CountComment, -1); err != nil {
+				return err
+			}
+		}
+
+		return s.AuditService.Record(ctx, user, audit.ActionDeleteReply, audit.TargetReply, replyId, data, removedSnapshot(data, false, user.Id, time.Now()))
+	}); err != nil {
 		return nil, err
 	}
 
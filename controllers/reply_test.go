@@ -0,0 +1,107 @@
+package controllers_test
+
+import (
+	"testing"
+
+	commentProto "github.com/forum-gamers/nine-tails-fox/generated/comment"
+	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	relationProto "github.com/forum-gamers/nine-tails-fox/generated/relation"
+	replyProto "github.com/forum-gamers/nine-tails-fox/generated/reply"
+	"google.golang.org/grpc/codes"
+)
+
+func TestCreateReply(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	bobCtx := authAs(t, s, bob)
+	client := replyProto.NewReplyServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	comment, err := commentProto.NewCommentServiceClient(s.Conn).CreateComment(bobCtx, &commentProto.CommentForm{Text: "question", PostId: data.XId})
+	mustNoError(t, err)
+
+	reply, err := client.CreateReply(ctx, &replyProto.CommentForm{Text: "answer", CommentId: comment.XId})
+	mustNoError(t, err)
+	if reply.UserId != alice || reply.Text != "answer" {
+		t.Errorf("CreateReply() = %v", reply)
+	}
+
+	result, err := postProto.NewPostServiceClient(s.Conn).FindById(ctx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+	if result.CountComment != 2 {
+		t.Errorf("countComment = %d, want 2", result.CountComment)
+	}
+
+	tests := []struct {
+		name string
+		form *replyProto.CommentForm
+		want codes.Code
+	}{
+		{"missing text", &replyProto.CommentForm{CommentId: comment.XId}, codes.InvalidArgument},
+		{"invalid comment id", &replyProto.CommentForm{Text: "text", CommentId: "invalid"}, codes.InvalidArgument},
+		{"missing comment", &replyProto.CommentForm{Text: "text", CommentId: missingId()}, codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.CreateReply(ctx, tt.form)
+			assertCode(t, err, tt.want)
+		})
+	}
+
+	t.Run("blocked by the comment author", func(t *testing.T) {
+		_, err := relationProto.NewRelationServiceClient(s.Conn).Block(bobCtx, &relationProto.UserIdPayload{UserId: "carol"})
+		mustNoError(t, err)
+
+		_, err = client.CreateReply(authAs(t, s, "carol"), &replyProto.CommentForm{Text: "hi", CommentId: comment.XId})
+		assertCode(t, err, codes.PermissionDenied)
+	})
+}
+
+func TestDeleteReply(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	bobCtx := authAs(t, s, bob)
+	client := replyProto.NewReplyServiceClient(s.Conn)
+	data := createPost(t, s, ctx, nil)
+
+	comment, err := commentProto.NewCommentServiceClient(s.Conn).CreateComment(ctx, &commentProto.CommentForm{Text: "question", PostId: data.XId})
+	mustNoError(t, err)
+
+	reply, err := client.CreateReply(bobCtx, &replyProto.CommentForm{Text: "answer", CommentId: comment.XId})
+	mustNoError(t, err)
+
+	_, err = client.DeleteReply(ctx, &replyProto.DeleteReplyPayload{CommentId: comment.XId, ReplyId: reply.XId})
+	assertCode(t, err, codes.PermissionDenied)
+
+	tests := []struct {
+		name    string
+		payload *replyProto.DeleteReplyPayload
+		want    codes.Code
+	}{
+		{"missing reply id", &replyProto.DeleteReplyPayload{CommentId: comment.XId}, codes.InvalidArgument},
+		{"missing comment id", &replyProto.DeleteReplyPayload{ReplyId: reply.XId}, codes.InvalidArgument},
+		{"invalid reply id", &replyProto.DeleteReplyPayload{CommentId: comment.XId, ReplyId: "invalid"}, codes.InvalidArgument},
+		{"missing reply", &replyProto.DeleteReplyPayload{CommentId: comment.XId, ReplyId: missingId()}, codes.NotFound},
+		{"missing comment", &replyProto.DeleteReplyPayload{CommentId: missingId(), ReplyId: reply.XId}, codes.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.DeleteReply(bobCtx, tt.payload)
+			assertCode(t, err, tt.want)
+		})
+	}
+
+	_, err = client.DeleteReply(bobCtx, &replyProto.DeleteReplyPayload{CommentId: comment.XId, ReplyId: reply.XId})
+	mustNoError(t, err)
+
+	_, err = client.DeleteReply(bobCtx, &replyProto.DeleteReplyPayload{CommentId: comment.XId, ReplyId: reply.XId})
+	assertCode(t, err, codes.NotFound)
+
+	result, err := postProto.NewPostServiceClient(s.Conn).FindById(ctx, &postProto.PostIdPayload{XId: data.XId})
+	mustNoError(t, err)
+	if result.CountComment != 1 {
+		t.Errorf("countComment = %d, want 1", result.CountComment)
+	}
+}
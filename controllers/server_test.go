@@ -0,0 +1,92 @@
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+
+	"github.com/forum-gamers/nine-tails-fox/config"
+	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	"github.com/forum-gamers/nine-tails-fox/testutil"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	alice = "alice"
+	bob   = "bob"
+	admin = "admin"
+)
+
+func newServer(t *testing.T, opts ...func(cfg *config.Config)) *testutil.Server {
+	t.Helper()
+
+	s, err := testutil.NewServer(opts...)
+	if err != nil {
+		t.Fatalf("NewServer() error = %v", err)
+	}
+	t.Cleanup(s.Close)
+	return s
+}
+
+func authAs(t *testing.T, s *testutil.Server, userId string) context.Context {
+	t.Helper()
+
+	accountType := "User"
+	if userId == admin {
+		accountType = "Admin"
+	}
+
+	ctx, err := s.AuthContext(context.Background(), userId, accountType)
+	if err != nil {
+		t.Fatalf("AuthContext() error = %v", err)
+	}
+	return ctx
+}
+
+func assertCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+
+	if got := status.Code(err); got != want {
+		t.Errorf("code = %s, want %s (%v)", got, want, err)
+	}
+}
+
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error = %v", err)
+	}
+}
+
+var postCount atomic.Int64
+
+// createPost publishes a post, public unless the form says otherwise. A nil
+// form creates a post that allows comments, and every post without text gets
+// its own so no two of them look alike.
+func createPost(t *testing.T, s *testutil.Server, ctx context.Context, form *postProto.PostForm) *postProto.Post {
+	t.Helper()
+
+	if form == nil {
+		form = &postProto.PostForm{AllowComment: true}
+	}
+
+	if form.Text == "" {
+		form.Text = fmt.Sprintf("post number %d about gaming", postCount.Add(1))
+	}
+
+	if form.Privacy == "" {
+		form.Privacy = "Public"
+	}
+
+	data, err := postProto.NewPostServiceClient(s.Conn).CreatePost(ctx, form)
+	mustNoError(t, err)
+	return data
+}
+
+func missingId() string {
+	return primitive.NewObjectID().Hex()
+}
@@ -0,0 +1,50 @@
+package controllers_test
+
+import (
+	"testing"
+
+	"github.com/forum-gamers/nine-tails-fox/config"
+	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	"google.golang.org/grpc/codes"
+)
+
+func TestSpamCheck(t *testing.T) {
+	s := newServer(t)
+	ctx := authAs(t, s, alice)
+	client := postProto.NewPostServiceClient(s.Conn)
+	form := func() *postProto.PostForm {
+		return &postProto.PostForm{Text: "buy cheap gold now", Privacy: "Public"}
+	}
+
+	createPost(t, s, ctx, form())
+	createPost(t, s, ctx, form())
+	if len(s.Store.Reports) != 0 {
+		t.Fatalf("reports = %v, want none below the quarantine score", s.Store.Reports)
+	}
+
+	quarantined := createPost(t, s, ctx, form())
+	if len(s.Store.Reports) != 1 {
+		t.Errorf("reports = %v, want the quarantined post queued", s.Store.Reports)
+	}
+
+	_, err := client.FindById(authAs(t, s, bob), &postProto.PostIdPayload{XId: quarantined.XId})
+	assertCode(t, err, codes.NotFound)
+
+	_, err = client.CreatePost(ctx, form())
+	assertCode(t, err, codes.InvalidArgument)
+}
+
+func TestSpamVelocity(t *testing.T) {
+	s := newServer(t, func(cfg *config.Config) {
+		cfg.Spam.MaxVelocity = 2
+	})
+	ctx := authAs(t, s, alice)
+
+	createPost(t, s, ctx, nil)
+	createPost(t, s, ctx, nil)
+
+	_, err := postProto.NewPostServiceClient(s.Conn).CreatePost(ctx, &postProto.PostForm{Text: "one more", Privacy: "Public"})
+	assertCode(t, err, codes.ResourceExhausted)
+
+	createPost(t, s, authAs(t, s, bob), nil)
+}
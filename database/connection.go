@@ -2,24 +2,115 @@ package database
 
 import (
 	"context"
+	"fmt"
 	"log"
-	"os"
-	"time"
+	"strconv"
 
-	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	"github.com/forum-gamers/nine-tails-fox/config"
+	"github.com/forum-gamers/nine-tails-fox/metrics"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
-func Connection() {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func NewDatabase(ctx context.Context, cfg config.Database) (Database, error) {
+	opts, err := ClientOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	connectCtx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(os.Getenv("DATABASE_URL")))
-	h.PanicIfError(err)
-	h.PanicIfError(client.Ping(ctx, readpref.Primary()))
+	client, err := mongo.Connect(connectCtx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.Ping(connectCtx, readpref.Primary()); err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
 
 	log.Println("Connected to the database")
-	DB = client.Database("Post")
+	return NewDatabaseFromClient(client, cfg.Name), nil
+}
+
+func NewDatabaseFromClient(client *mongo.Client, name string) Database {
+	return &DatabaseImpl{client: client, db: client.Database(name)}
+}
+
+func ClientOptions(cfg config.Database) (*options.ClientOptions, error) {
+	opts := options.Client().
+		ApplyURI(cfg.Url).
+		SetMaxPoolSize(cfg.MaxPoolSize).
+		SetMinPoolSize(cfg.MinPoolSize).
+		SetConnectTimeout(cfg.ConnectTimeout).
+		SetMonitor(CombineCommandMonitors(metrics.NewCommandMonitor(), otelmongo.NewMonitor())).
+		SetPoolMonitor(metrics.NewPoolMonitor())
+
+	if cfg.AppName != "" {
+		opts.SetAppName(cfg.AppName)
+	}
+
+	if cfg.SocketTimeout > 0 {
+		opts.SetSocketTimeout(cfg.SocketTimeout)
+	}
+
+	if cfg.ServerSelectionTimeout > 0 {
+		opts.SetServerSelectionTimeout(cfg.ServerSelectionTimeout)
+	}
+
+	if cfg.MaxConnIdleTime > 0 {
+		opts.SetMaxConnIdleTime(cfg.MaxConnIdleTime)
+	}
+
+	if cfg.ReadConcern != "" {
+		opts.SetReadConcern(&readconcern.ReadConcern{Level: cfg.ReadConcern})
+	}
+
+	if cfg.WriteConcern != "" {
+		wc, err := parseWriteConcern(cfg.WriteConcern)
+		if err != nil {
+			return nil, err
+		}
+		opts.SetWriteConcern(wc)
+	}
+
+	return opts, nil
+}
+
+func parseWriteConcern(val string) (*writeconcern.WriteConcern, error) {
+	if val == "majority" {
+		return writeconcern.Majority(), nil
+	}
+
+	w, err := strconv.Atoi(val)
+	if err != nil || w < 0 {
+		return nil, fmt.Errorf("invalid write concern %q, must be majority or a non negative number", val)
+	}
+	return &writeconcern.WriteConcern{W: w}, nil
+}
+
+func (d *DatabaseImpl) Collection(name string) *mongo.Collection {
+	return d.db.Collection(name)
+}
+
+func (d *DatabaseImpl) Client() *mongo.Client {
+	return d.client
+}
+
+func (d *DatabaseImpl) Name() string {
+	return d.db.Name()
+}
+
+func (d *DatabaseImpl) Ping(ctx context.Context) error {
+	return d.client.Ping(ctx, readpref.Primary())
+}
+
+func (d *DatabaseImpl) Disconnect(ctx context.Context) error {
+	return d.client.Disconnect(ctx)
 }
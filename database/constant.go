@@ -1,5 +1,20 @@
 package database
 
-import "go.mongodb.org/mongo-driver/mongo"
+import (
+	"context"
 
-var DB *mongo.Database
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type Database interface {
+	Collection(name string) *mongo.Collection
+	Client() *mongo.Client
+	Name() string
+	Ping(ctx context.Context) error
+	Disconnect(ctx context.Context) error
+}
+
+type DatabaseImpl struct {
+	client *mongo.Client
+	db     *mongo.Database
+}
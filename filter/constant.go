@@ -0,0 +1,52 @@
+package filter
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	Reject = "reject"
+	Mask   = "mask"
+	Flag   = "flag"
+)
+
+const MASKRUNE = '*'
+
+type ContentFilter interface {
+	Check(text string) (Result, error)
+	Reload() (bool, error)
+}
+
+type ContentFilterImpl struct {
+	mu      sync.RWMutex
+	path    string
+	modTime time.Time
+	rules   []rule
+}
+
+// RuleSet is the layout of the rules file.
+type RuleSet struct {
+	Rules []Rule `yaml:"rules" toml:"rules" json:"rules"`
+}
+
+type Rule struct {
+	Name    string   `yaml:"name" toml:"name" json:"name"`
+	Action  string   `yaml:"action" toml:"action" json:"action"`
+	Words   []string `yaml:"words" toml:"words" json:"words"`
+	Domains []string `yaml:"domains" toml:"domains" json:"domains"`
+}
+
+// Result is the outcome of a check that did not reject the text. Text has
+// every masked match replaced and Flags lists the rules asking for review.
+type Result struct {
+	Text  string
+	Flags []string
+}
+
+type rule struct {
+	name    string
+	action  string
+	words   map[string]struct{}
+	domains []string
+}
@@ -0,0 +1,213 @@
+package filter
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	"google.golang.org/grpc/codes"
+	"gopkg.in/yaml.v3"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://)?(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,}(?::\d+)?(?:/[^\s]*)?`)
+
+// NewContentFilter loads the rules file at path. An empty path yields a filter
+// that lets every text through.
+func NewContentFilter(path string) (ContentFilter, error) {
+	f := &ContentFilterImpl{path: path}
+	if path == "" {
+		return f, nil
+	}
+
+	if _, err := f.Reload(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Reload reads the rules file again when it changed since the last load. A
+// broken file keeps the previous rules in place.
+func (f *ContentFilterImpl) Reload() (bool, error) {
+	if f.path == "" {
+		return false, nil
+	}
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return false, fmt.Errorf("failed to stat content filter rules : %w", err)
+	}
+
+	f.mu.RLock()
+	unchanged := info.ModTime().Equal(f.modTime)
+	f.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	rules, err := loadRules(f.path)
+	if err != nil {
+		return false, err
+	}
+
+	f.mu.Lock()
+	f.rules, f.modTime = rules, info.ModTime()
+	f.mu.Unlock()
+	return true, nil
+}
+
+func (f *ContentFilterImpl) Check(text string) (Result, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	result := Result{Text: text}
+	if len(f.rules) < 1 || text == "" {
+		return result, nil
+	}
+
+	var masks []span
+	for _, rule := range f.rules {
+		matches := rule.match(text)
+		if len(matches) < 1 {
+			continue
+		}
+
+		switch rule.action {
+		case Reject:
+			return Result{}, h.NewAppError(codes.InvalidArgument, "text contains blocked content")
+		case Mask:
+			masks = append(masks, matches...)
+		case Flag:
+			if !slices.Contains(result.Flags, rule.name) {
+				result.Flags = append(result.Flags, rule.name)
+			}
+		}
+	}
+
+	if len(masks) > 0 {
+		result.Text = mask(text, masks)
+	}
+	return result, nil
+}
+
+// FindLinks returns every link or bare domain in text.
+func FindLinks(text string) []string {
+	return linkPattern.FindAllString(text, -1)
+}
+
+func (r rule) match(text string) (result []span) {
+	if len(r.words) > 0 {
+		for _, word := range words(text) {
+			if _, ok := r.words[normalize(text[word.start:word.end])]; ok {
+				result = append(result, word)
+			}
+		}
+	}
+
+	if len(r.domains) > 0 {
+		for _, link := range linkPattern.FindAllStringIndex(text, -1) {
+			if r.isBlocked(host(text[link[0]:link[1]])) {
+				result = append(result, span{link[0], link[1]})
+			}
+		}
+	}
+	return
+}
+
+func (r rule) isBlocked(host string) bool {
+	for _, domain := range r.domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func host(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+func mask(text string, spans []span) string {
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	var b strings.Builder
+	last := 0
+	for _, s := range spans {
+		if s.end <= last {
+			continue
+		}
+
+		if s.start < last {
+			s.start = last
+		}
+
+		b.WriteString(text[last:s.start])
+		b.WriteString(strings.Repeat(string(MASKRUNE), len([]rune(text[s.start:s.end]))))
+		last = s.end
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+func loadRules(path string) ([]rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content filter rules : %w", err)
+	}
+
+	var set RuleSet
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &set)
+	case ".toml":
+		err = toml.Unmarshal(data, &set)
+	default:
+		return nil, fmt.Errorf("unsupported content filter rules extension %q", filepath.Ext(path))
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse content filter rules %s : %w", path, err)
+	}
+
+	rules := make([]rule, 0, len(set.Rules))
+	for i, data := range set.Rules {
+		action := strings.ToLower(data.Action)
+		if !slices.Contains([]string{Reject, Mask, Flag}, action) {
+			return nil, fmt.Errorf("content filter rule %d has action %q, must be one of reject,mask,flag", i, data.Action)
+		}
+
+		name := data.Name
+		if name == "" {
+			name = fmt.Sprintf("rule-%d", i)
+		}
+
+		r := rule{name: name, action: action, words: map[string]struct{}{}}
+		for _, word := range data.Words {
+			if word = strings.TrimSpace(word); word != "" {
+				r.words[normalize(word)] = struct{}{}
+			}
+		}
+
+		for _, domain := range data.Domains {
+			if domain = strings.Trim(strings.ToLower(strings.TrimSpace(domain)), "."); domain != "" {
+				r.domains = append(r.domains, domain)
+			}
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
@@ -0,0 +1,65 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+var leetspeak = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+	'8': 'b',
+	'9': 'g',
+	'@': 'a',
+	'$': 's',
+}
+
+// normalize folds a word to the form banned words are compared in: lower
+// case, without diacritics and with leetspeak digits and symbols replaced.
+func normalize(word string) string {
+	folded, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), word)
+	if err != nil {
+		folded = word
+	}
+
+	return strings.Map(func(r rune) rune {
+		if l, ok := leetspeak[r]; ok {
+			return l
+		}
+		return unicode.ToLower(r)
+	}, folded)
+}
+
+func isWordRune(r rune) bool {
+	_, leet := leetspeak[r]
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || leet
+}
+
+// span is a byte range of the original text.
+type span struct{ start, end int }
+
+func words(text string) (result []span) {
+	start := -1
+	for i, r := range text {
+		switch {
+		case isWordRune(r) && start < 0:
+			start = i
+		case !isWordRune(r) && start >= 0:
+			result = append(result, span{start, i})
+			start = -1
+		}
+	}
+
+	if start >= 0 {
+		result = append(result, span{start, len(text)})
+	}
+	return
+}
@@ -1,31 +1,39 @@
 package generated
 
 import (
+	"time"
+
+	auditProto "github.com/forum-gamers/nine-tails-fox/generated/audit"
 	bookmarkProto "github.com/forum-gamers/nine-tails-fox/generated/bookmark"
 	commentProto "github.com/forum-gamers/nine-tails-fox/generated/comment"
+	moderationProto "github.com/forum-gamers/nine-tails-fox/generated/moderation"
 	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	preferenceProto "github.com/forum-gamers/nine-tails-fox/generated/preference"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
 	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
+	"github.com/forum-gamers/nine-tails-fox/pkg/moderation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/poll"
 	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/preference"
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 func ParsePostRespToProto(datas []post.PostResponse) (result []*postProto.PostResponse) {
 	for _, data := range datas {
-		medias := make([]*postProto.Media, 0)
-
-		if data.Media != nil || len(data.Media) > 0 {
-			for _, media := range data.Media {
-				medias = append(medias, &postProto.Media{
-					Url:  media.Url,
-					Type: media.Type,
-					Id:   media.Id,
-				})
-			}
+		deletedAt, publishAt := "", ""
+		if data.DeletedAt != nil {
+			deletedAt = data.DeletedAt.String()
 		}
+
+		if data.PublishAt != nil {
+			publishAt = data.PublishAt.String()
+		}
+
 		result = append(result, &postProto.PostResponse{
 			XId:          data.Id.Hex(),
 			UserId:       data.UserId,
 			Text:         data.Text,
-			Media:        medias,
+			Media:        ParseMediaToProto(data.Media),
 			AllowComment: data.AllowComment,
 			CreatedAt:    data.CreatedAt.String(),
 			UpdatedAt:    data.UpdatedAt.String(),
@@ -33,33 +41,133 @@ func ParsePostRespToProto(datas []post.PostResponse) (result []*postProto.PostRe
 			CountShare:   int64(data.CountShare),
 			IsLiked:      data.IsLiked,
 			IsShared:     data.IsShared,
+			IsPinned:     data.IsPinned,
 			Tags:         data.Tags,
 			Privacy:      data.Privacy,
 			TotalData:    int64(data.TotalData),
 			CountComment: int64(data.CountComment),
+			DeletedAt:    deletedAt,
+			Status:       data.Status,
+			PublishAt:    publishAt,
+			Poll:         ParsePollToProto(data.Poll),
+			Previews:     ParseLinkPreviewsToProto(data.Previews),
+		})
+	}
+	return
+}
+
+func ParsePostToProto(data post.Post) *postProto.Post {
+	publishAt := ""
+	if data.PublishAt != nil {
+		publishAt = data.PublishAt.String()
+	}
+
+	return &postProto.Post{
+		XId:          data.Id.Hex(),
+		UserId:       data.UserId,
+		Text:         data.Text,
+		Media:        ParseMediaToProto(data.Media),
+		AllowComment: data.AllowComment,
+		CreatedAt:    data.CreatedAt.String(),
+		UpdatedAt:    data.UpdatedAt.String(),
+		Tags:         data.Tags,
+		Privacy:      data.Privacy,
+		Status:       data.Status,
+		PublishAt:    publishAt,
+		Poll:         ParsePollToProto(data.Poll),
+		Previews:     ParseLinkPreviewsToProto(data.Previews),
+	}
+}
+
+func ParseMediaToProto(datas []post.Media) []*postProto.Media {
+	result := make([]*postProto.Media, 0, len(datas))
+	for _, data := range datas {
+		result = append(result, &postProto.Media{
+			Id:        data.Id,
+			Type:      data.Type,
+			Url:       data.Url,
+			Width:     int32(data.Width),
+			Height:    int32(data.Height),
+			Duration:  data.Duration,
+			Size:      data.Size,
+			Blurhash:  data.Blurhash,
+			Alt:       data.Alt,
+			Sensitive: data.Sensitive,
+		})
+	}
+	return result
+}
+
+func ParseMediaItemsToProto(datas []post.MediaItem) (result []*postProto.MediaItem) {
+	for _, data := range datas {
+		result = append(result, &postProto.MediaItem{
+			PostId:    data.PostId.Hex(),
+			UserId:    data.UserId,
+			Index:     int32(data.Index),
+			Media:     ParseMediaToProto([]post.Media{data.Media})[0],
+			CreatedAt: data.CreatedAt.String(),
 		})
 	}
 	return
 }
 
+func ParseLinkPreviewsToProto(datas []post.LinkPreview) []*postProto.LinkPreview {
+	result := make([]*postProto.LinkPreview, 0, len(datas))
+	for _, data := range datas {
+		result = append(result, &postProto.LinkPreview{
+			Url:         data.Url,
+			Title:       data.Title,
+			Description: data.Description,
+			Image:       data.Image,
+			SiteName:    data.SiteName,
+			Type:        data.Type,
+		})
+	}
+	return result
+}
+
+func ParsePollToProto(data *post.Poll) *postProto.Poll {
+	if data == nil {
+		return nil
+	}
+
+	options := make([]*postProto.PollOption, 0, len(data.Options))
+	for _, option := range data.Options {
+		options = append(options, &postProto.PollOption{
+			Text:  option.Text,
+			Count: int64(option.Count),
+		})
+	}
+
+	myVotes := make([]int32, 0, len(data.MyVotes))
+	for _, vote := range data.MyVotes {
+		myVotes = append(myVotes, int32(vote))
+	}
+
+	closesAt := ""
+	if data.ClosesAt != nil {
+		closesAt = data.ClosesAt.String()
+	}
+
+	return &postProto.Poll{
+		Options:        options,
+		MultipleChoice: data.MultipleChoice,
+		ClosesAt:       closesAt,
+		HideResults:    data.HideResults,
+		TotalVotes:     int64(data.TotalVotes),
+		MyVotes:        myVotes,
+		ResultsHidden:  data.ResultsHidden,
+		Closed:         poll.IsClosed(data, time.Now()),
+	}
+}
+
 func ParseBookmarkPostRespToProto(datas []post.PostResponse) (result []*bookmarkProto.PostResponse) {
 	for _, data := range datas {
-		medias := make([]*bookmarkProto.Media, 0)
-
-		if data.Media != nil || len(data.Media) > 0 {
-			for _, media := range data.Media {
-				medias = append(medias, &bookmarkProto.Media{
-					Url:  media.Url,
-					Type: media.Type,
-					Id:   media.Id,
-				})
-			}
-		}
 		result = append(result, &bookmarkProto.PostResponse{
 			XId:          data.Id.Hex(),
 			UserId:       data.UserId,
 			Text:         data.Text,
-			Media:        medias,
+			Media:        parseBookmarkMediaToProto(data.Media),
 			AllowComment: data.AllowComment,
 			CreatedAt:    data.CreatedAt.String(),
 			UpdatedAt:    data.UpdatedAt.String(),
@@ -71,11 +179,46 @@ func ParseBookmarkPostRespToProto(datas []post.PostResponse) (result []*bookmark
 			Privacy:      data.Privacy,
 			TotalData:    int64(data.TotalData),
 			CountComment: int64(data.CountComment),
+			Previews:     parseBookmarkLinkPreviewsToProto(data.Previews),
 		})
 	}
 	return
 }
 
+func parseBookmarkMediaToProto(datas []post.Media) []*bookmarkProto.Media {
+	result := make([]*bookmarkProto.Media, 0, len(datas))
+	for _, data := range datas {
+		result = append(result, &bookmarkProto.Media{
+			Id:        data.Id,
+			Type:      data.Type,
+			Url:       data.Url,
+			Width:     int32(data.Width),
+			Height:    int32(data.Height),
+			Duration:  data.Duration,
+			Size:      data.Size,
+			Blurhash:  data.Blurhash,
+			Alt:       data.Alt,
+			Sensitive: data.Sensitive,
+		})
+	}
+	return result
+}
+
+func parseBookmarkLinkPreviewsToProto(datas []post.LinkPreview) []*bookmarkProto.LinkPreview {
+	result := make([]*bookmarkProto.LinkPreview, 0, len(datas))
+	for _, data := range datas {
+		result = append(result, &bookmarkProto.LinkPreview{
+			Url:         data.Url,
+			Title:       data.Title,
+			Description: data.Description,
+			Image:       data.Image,
+			SiteName:    data.SiteName,
+			Type:        data.Type,
+		})
+	}
+	return result
+}
+
 func ParseTagsRespToProto(datas []post.TopTags) (result []*postProto.TopTag) {
 	for _, data := range datas {
 		postIds := make([]string, 0)
@@ -120,3 +263,89 @@ func ParseCommentRespToProto(datas []comment.CommentResponse) (result []*comment
 	}
 	return
 }
+
+func ParseQueueItemsToProto(datas []moderation.QueueItem) (result []*moderationProto.QueueItem) {
+	for _, data := range datas {
+		commentId := ""
+		if !data.CommentId.IsZero() {
+			commentId = data.CommentId.Hex()
+		}
+
+		result = append(result, &moderationProto.QueueItem{
+			TargetType:      data.TargetType,
+			TargetId:        data.TargetId.Hex(),
+			CommentId:       commentId,
+			PostId:          data.PostId.Hex(),
+			AuthorId:        data.AuthorId,
+			ReportCount:     int64(data.ReportCount),
+			Reasons:         data.Reasons,
+			Texts:           data.Texts,
+			FirstReportedAt: data.FirstReportedAt.String(),
+			LastReportedAt:  data.LastReportedAt.String(),
+		})
+	}
+	return
+}
+
+func ParseAuditEntriesToProto(datas []audit.Entry) (result []*auditProto.AuditEntry) {
+	for _, data := range datas {
+		result = append(result, &auditProto.AuditEntry{
+			XId:        data.Id.Hex(),
+			ActorId:    data.ActorId,
+			ActorType:  data.ActorType,
+			Action:     data.Action,
+			TargetType: data.TargetType,
+			TargetId:   data.TargetId.Hex(),
+			Before:     parseSnapshot(data.Before),
+			After:      parseSnapshot(data.After),
+			RequestId:  data.RequestId,
+			CreatedAt:  data.CreatedAt.String(),
+		})
+	}
+	return
+}
+
+func ParsePreferenceToProto(data preference.UserPreference) *preferenceProto.Preference {
+	tags := make([]*preferenceProto.TagPreference, 0, len(data.Tags))
+	for _, tag := range data.Tags {
+		tags = append(tags, &preferenceProto.TagPreference{
+			Value:     tag.Value,
+			Weight:    tag.Weight,
+			CreatedAt: tag.CreatedAt.String(),
+			UpdatedAt: tag.UpdatedAt.String(),
+		})
+	}
+
+	mutedTags := make([]string, 0, len(data.MutedTags))
+	mutedTags = append(mutedTags, data.MutedTags...)
+
+	return &preferenceProto.Preference{
+		UserId:    data.UserId,
+		Tags:      tags,
+		MutedTags: mutedTags,
+		UpdatedAt: data.UpdatedAt.String(),
+	}
+}
+
+func ParseTagSuggestionsToProto(datas []post.TopTags) (result []*preferenceProto.TagSuggestion) {
+	for _, data := range datas {
+		result = append(result, &preferenceProto.TagSuggestion{
+			Tag:   data.Id,
+			Count: int64(data.Count),
+		})
+	}
+	return
+}
+
+// parseSnapshot renders a stored snapshot as relaxed extended JSON.
+func parseSnapshot(data any) string {
+	if data == nil {
+		return ""
+	}
+
+	raw, err := bson.MarshalExtJSON(data, false, false)
+	if err != nil {
+		return ""
+	}
+	return string(raw)
+}
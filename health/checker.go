@@ -0,0 +1,60 @@
+package health
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func NewHealthChecker(db database.Database, interval time.Duration, services ...string) HealthChecker {
+	return &HealthCheckerImpl{
+		server:   health.NewServer(),
+		db:       db,
+		services: append([]string{""}, services...),
+		interval: interval,
+		timeout:  interval / 2,
+	}
+}
+
+func (c *HealthCheckerImpl) Server() *health.Server {
+	return c.server
+}
+
+func (c *HealthCheckerImpl) Run(ctx context.Context) {
+	c.check(ctx)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.check(ctx)
+		}
+	}
+}
+
+func (c *HealthCheckerImpl) Shutdown() {
+	c.server.Shutdown()
+}
+
+func (c *HealthCheckerImpl) check(ctx context.Context) {
+	pingCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	servingStatus := healthpb.HealthCheckResponse_SERVING
+	if err := c.db.Ping(pingCtx); err != nil {
+		log.Printf("Database health check failed : %s", err.Error())
+		servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	for _, service := range c.services {
+		c.server.SetServingStatus(service, servingStatus)
+	}
+}
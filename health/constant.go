@@ -0,0 +1,23 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	"google.golang.org/grpc/health"
+)
+
+type HealthChecker interface {
+	Run(ctx context.Context)
+	Shutdown()
+	Server() *health.Server
+}
+
+type HealthCheckerImpl struct {
+	server   *health.Server
+	db       database.Database
+	services []string
+	interval time.Duration
+	timeout  time.Duration
+}
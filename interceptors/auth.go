@@ -2,7 +2,7 @@ package interceptors
 
 import (
 	"context"
-	"os"
+	"strings"
 
 	"github.com/golang-jwt/jwt"
 	"google.golang.org/grpc"
@@ -12,6 +12,12 @@ import (
 )
 
 func (i *InterceptorImpl) UnaryAuthentication(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	for _, prefix := range PUBLICMETHODPREFIXES {
+		if strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+	}
+
 	metadata, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		return nil, status.Error(codes.Unauthenticated, "missing metadata")
@@ -24,7 +30,7 @@ func (i *InterceptorImpl) UnaryAuthentication(ctx context.Context, req any, info
 
 	claim := jwt.MapClaims{}
 	if token, err := jwt.ParseWithClaims(values[0], &claim, func(t *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("SECRET")), nil
+		return []byte(i.Secret), nil
 	}); err != nil || !token.Valid {
 		return nil, status.Error(codes.Unauthenticated, "missing or invalid token")
 	}
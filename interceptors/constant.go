@@ -2,7 +2,10 @@ package interceptors
 
 import (
 	"context"
+	"time"
 
+	"github.com/forum-gamers/nine-tails-fox/config"
+	"github.com/forum-gamers/nine-tails-fox/pkg/idempotency"
 	"github.com/forum-gamers/nine-tails-fox/pkg/user"
 	"google.golang.org/grpc"
 )
@@ -10,17 +13,42 @@ import (
 type Interceptor interface {
 	UnaryAuthentication(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error)
 	GetUserFromCtx(ctx context.Context) user.User
+	RequestId(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error)
+	GetRequestIdFromCtx(ctx context.Context) string
 	Logging(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error)
+	Metrics(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error)
+	Recovery(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error)
+	Pagination(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error)
+	Idempotency(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error)
 }
 
-type InterceptorImpl struct{}
+type InterceptorImpl struct {
+	Secret          string
+	DefaultPageSize int32
+	MaxPageSize     int32
+	IdempotencyTTL  time.Duration
+	IdempotencyRepo idempotency.IdempotencyRepo
+}
 
-func NewInterCeptor() Interceptor {
-	return &InterceptorImpl{}
+func NewInterCeptor(cfg *config.Config, idempotencyRepo idempotency.IdempotencyRepo) Interceptor {
+	return &InterceptorImpl{
+		Secret:          cfg.Secret,
+		DefaultPageSize: cfg.Pagination.DefaultLimit,
+		MaxPageSize:     cfg.Pagination.MaxLimit,
+		IdempotencyTTL:  cfg.Idempotency.TTL,
+		IdempotencyRepo: idempotencyRepo,
+	}
 }
 
 type ContextKey string
 
 const (
-	CONTEXTUSERKEY ContextKey = "user"
+	CONTEXTUSERKEY      ContextKey = "user"
+	CONTEXTREQUESTIDKEY ContextKey = "requestId"
 )
+
+const INCIDENTIDKEY = "x-incident-id"
+
+const REQUESTIDKEY = "x-request-id"
+
+var PUBLICMETHODPREFIXES = []string{"/grpc.health.v1.Health/", "/grpc.reflection."}
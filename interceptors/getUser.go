@@ -18,9 +18,9 @@ func (i *InterceptorImpl) GetUserFromCtx(ctx context.Context) user.User {
 	for key, val := range claim {
 		switch key {
 		case "id":
-			user.Id = val.(string)
+			user.Id, _ = val.(string)
 		case "accountType":
-			user.AccountType = val.(string)
+			user.AccountType, _ = val.(string)
 		default:
 			continue
 		}
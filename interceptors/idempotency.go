@@ -0,0 +1,100 @@
+package interceptors
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/idempotency"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func (i *InterceptorImpl) Idempotency(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+	if !strings.HasPrefix(method, "Create") {
+		return handler(ctx, req)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(idempotency.HEADERKEY)
+	if len(values) < 1 || values[0] == "" {
+		return handler(ctx, req)
+	}
+
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to hash request")
+	}
+	hash := sha256.Sum256(payload)
+
+	userId := i.GetUserFromCtx(ctx).Id
+	record := idempotency.Record{
+		Key:         strings.Join([]string{userId, info.FullMethod, values[0]}, ":"),
+		UserId:      userId,
+		Method:      info.FullMethod,
+		RequestHash: hex.EncodeToString(hash[:]),
+		Status:      idempotency.Pending,
+		CreatedAt:   time.Now(),
+		ExpiresAt:   time.Now().Add(i.IdempotencyTTL),
+	}
+
+	if err := i.IdempotencyRepo.Reserve(ctx, &record); err != nil {
+		if status.Code(err) != codes.AlreadyExists {
+			return nil, err
+		}
+		return i.replay(ctx, record)
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		i.IdempotencyRepo.Release(context.WithoutCancel(ctx), record.Key)
+		return nil, err
+	}
+
+	if msg, ok := resp.(proto.Message); ok {
+		if value, err := anypb.New(msg); err == nil {
+			if data, err := proto.Marshal(value); err == nil {
+				i.IdempotencyRepo.Complete(context.WithoutCancel(ctx), record.Key, data)
+			}
+		}
+	}
+	return resp, nil
+}
+
+func (i *InterceptorImpl) replay(ctx context.Context, record idempotency.Record) (any, error) {
+	var existing idempotency.Record
+	if err := i.IdempotencyRepo.FindByKey(ctx, record.Key, &existing); err != nil {
+		return nil, err
+	}
+
+	if existing.RequestHash != record.RequestHash {
+		return nil, status.Error(codes.InvalidArgument, "idempotency key was already used with a different request")
+	}
+
+	if existing.Status != idempotency.Completed {
+		return nil, status.Error(codes.Aborted, "a request with the same idempotency key is still in progress")
+	}
+
+	var value anypb.Any
+	if err := proto.Unmarshal(existing.Response, &value); err != nil {
+		return nil, status.Error(codes.Internal, "failed to read stored response")
+	}
+
+	resp, err := value.UnmarshalNew()
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to read stored response")
+	}
+	return resp, nil
+}
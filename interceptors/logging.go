@@ -8,7 +8,7 @@ import (
 )
 
 func (i *InterceptorImpl) Logging(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-	log.Printf("Request : %s", info.FullMethod)
+	log.Printf("Request : %s request id : %s", info.FullMethod, i.GetRequestIdFromCtx(ctx))
 
 	return handler(ctx, req)
 }
@@ -0,0 +1,21 @@
+package interceptors
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/metrics"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func (i *InterceptorImpl) Metrics(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+
+	code := status.Code(err).String()
+	metrics.RequestTotal.WithLabelValues(info.FullMethod, code).Inc()
+	metrics.RequestDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
@@ -0,0 +1,37 @@
+package interceptors
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func (i *InterceptorImpl) Pagination(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	if page := fields.ByName("page"); page != nil && page.Kind() == protoreflect.Int32Kind {
+		if m.Get(page).Int() < 1 {
+			m.Set(page, protoreflect.ValueOfInt32(1))
+		}
+	}
+
+	if limit := fields.ByName("limit"); limit != nil && limit.Kind() == protoreflect.Int32Kind {
+		switch val := int32(m.Get(limit).Int()); true {
+		case val < 1:
+			m.Set(limit, protoreflect.ValueOfInt32(i.DefaultPageSize))
+		case val > i.MaxPageSize:
+			m.Set(limit, protoreflect.ValueOfInt32(i.MaxPageSize))
+		default:
+			break
+		}
+	}
+
+	return handler(ctx, req)
+}
@@ -0,0 +1,27 @@
+package interceptors
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func (i *InterceptorImpl) Recovery(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			incidentId := primitive.NewObjectID().Hex()
+			log.Printf("Panic recovered : %s incident : %s error : %v\n%s", info.FullMethod, incidentId, r, debug.Stack())
+
+			grpc.SetTrailer(ctx, metadata.Pairs(INCIDENTIDKEY, incidentId))
+			resp, err = nil, status.Errorf(codes.Internal, "internal server error, incident id : %s", incidentId)
+		}
+	}()
+
+	return handler(ctx, req)
+}
@@ -0,0 +1,32 @@
+package interceptors
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestId reuses the caller's x-request-id or assigns a new one, stores it
+// in the context and echoes it back in the response header.
+func (i *InterceptorImpl) RequestId(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	var requestId string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(REQUESTIDKEY); len(values) > 0 && values[0] != "" {
+			requestId = values[0]
+		}
+	}
+
+	if requestId == "" {
+		requestId = primitive.NewObjectID().Hex()
+	}
+
+	grpc.SetHeader(ctx, metadata.Pairs(REQUESTIDKEY, requestId))
+	return handler(context.WithValue(ctx, CONTEXTREQUESTIDKEY, requestId), req)
+}
+
+func (i *InterceptorImpl) GetRequestIdFromCtx(ctx context.Context) string {
+	requestId, _ := ctx.Value(CONTEXTREQUESTIDKEY).(string)
+	return requestId
+}
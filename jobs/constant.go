@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"context"
+	"time"
+)
+
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+type Runner interface {
+	Run(ctx context.Context)
+}
+
+type RunnerImpl struct {
+	jobs []Job
+}
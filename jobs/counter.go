@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+)
+
+func NewCounterReconciler(repo post.PostRepo, interval time.Duration) Job {
+	return Job{
+		Name:     "reconcile-counters",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			modified, err := repo.ReconcileCounters(ctx)
+			if err != nil {
+				return err
+			}
+
+			if modified > 0 {
+				log.Printf("Reconciled counters of %d posts", modified)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,27 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/filter"
+)
+
+func NewContentFilterReloader(contentFilter filter.ContentFilter, interval time.Duration) Job {
+	return Job{
+		Name:     "reload-content-filter",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			reloaded, err := contentFilter.Reload()
+			if err != nil {
+				return err
+			}
+
+			if reloaded {
+				log.Println("Reloaded content filter rules")
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+func NewRunner(jobs ...Job) Runner {
+	return &RunnerImpl{jobs: jobs}
+}
+
+func (r *RunnerImpl) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range r.jobs {
+		if job.Interval <= 0 {
+			log.Printf("Job %s is disabled", job.Name)
+			continue
+		}
+
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			r.schedule(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+func (r *RunnerImpl) schedule(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Job %s failed : %s", job.Name, err.Error())
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const PUBLISHBATCHSIZE = 100
+
+// NewPostScheduler publishes scheduled posts once they are due. publish must
+// run the same checks and side effects as creating a post directly.
+func NewPostScheduler(repo post.PostRepo, interval time.Duration, publish func(ctx context.Context, data post.Post) error) Job {
+	return Job{
+		Name:     "publish-scheduled-posts",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			now := time.Now()
+			published := 0
+			var last primitive.ObjectID
+			for {
+				datas, err := repo.FindDuePosts(ctx, now, last, PUBLISHBATCHSIZE)
+				if err != nil {
+					return err
+				}
+
+				for _, data := range datas {
+					last = data.Id
+					if err := publish(ctx, data); err != nil {
+						switch status.Code(err) {
+						// the owner published or deleted it in between
+						case codes.NotFound:
+						// rejected by the content filter or as spam, publish moved it back to drafts
+						case codes.InvalidArgument:
+							log.Printf("Scheduled post %s was rejected : %s", data.Id.Hex(), err.Error())
+						// the owner is over the posting velocity, it stays due for the next run
+						case codes.ResourceExhausted:
+						default:
+							if ctx.Err() != nil {
+								return ctx.Err()
+							}
+							// one broken post must not hold back the others, it stays due
+							log.Printf("Failed to publish scheduled post %s : %s", data.Id.Hex(), err.Error())
+						}
+						continue
+					}
+					published++
+				}
+
+				if len(datas) < PUBLISHBATCHSIZE {
+					break
+				}
+			}
+
+			if published > 0 {
+				log.Printf("Published %d scheduled posts", published)
+			}
+			return nil
+		},
+	}
+}
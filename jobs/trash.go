@@ -0,0 +1,73 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
+	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
+	"github.com/forum-gamers/nine-tails-fox/pkg/like"
+	"github.com/forum-gamers/nine-tails-fox/pkg/poll"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/share"
+)
+
+const PURGEBATCHSIZE = 100
+
+// NewTrashPurger permanently deletes posts that stayed in the trash past
+// retention. onPurged runs for every post once its deletion committed, a
+// failure there is logged since the post cannot be brought back.
+func NewTrashPurger(postRepo post.PostRepo, likeRepo like.LikeRepo, commentRepo comment.CommentRepo, shareRepo share.ShareRepo, pollRepo poll.PollRepo, auditService audit.AuditService, retention, interval time.Duration, onPurged func(ctx context.Context, data post.Post) error) Job {
+	return Job{
+		Name:     "purge-trash",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			before := time.Now().Add(-retention)
+			for {
+				datas, err := postRepo.FindDeletedBefore(ctx, before, PURGEBATCHSIZE)
+				if err != nil {
+					return err
+				}
+
+				for _, data := range datas {
+					if err := postRepo.WithTransaction(ctx, func(ctx context.Context) error {
+						if err := likeRepo.DeletePostLikes(ctx, data.Id); err != nil {
+							return err
+						}
+
+						if err := shareRepo.DeleteMany(ctx, data.Id); err != nil {
+							return err
+						}
+
+						if err := commentRepo.DeleteMany(ctx, data.Id); err != nil {
+							return err
+						}
+
+						if err := pollRepo.DeletePostVotes(ctx, data.Id); err != nil {
+							return err
+						}
+
+						if err := postRepo.DeleteOne(ctx, data.Id); err != nil {
+							return err
+						}
+
+						return auditService.Record(ctx, audit.System, audit.ActionPurgePost, audit.TargetPost, data.Id, data, nil)
+					}); err != nil {
+						return err
+					}
+
+					if err := onPurged(ctx, data); err != nil {
+						log.Printf("Failed to hand off purged post %s : %s", data.Id.Hex(), err.Error())
+					}
+				}
+
+				if len(datas) < PURGEBATCHSIZE {
+					break
+				}
+			}
+
+			return commentRepo.PurgeDeleted(ctx, before)
+		},
+	}
+}
@@ -1,41 +1,90 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/forum-gamers/nine-tails-fox/config"
 	cc "github.com/forum-gamers/nine-tails-fox/controllers"
 	"github.com/forum-gamers/nine-tails-fox/database"
+	"github.com/forum-gamers/nine-tails-fox/filter"
+	auditProto "github.com/forum-gamers/nine-tails-fox/generated/audit"
 	bookmarkProto "github.com/forum-gamers/nine-tails-fox/generated/bookmark"
 	commentProto "github.com/forum-gamers/nine-tails-fox/generated/comment"
 	likeProto "github.com/forum-gamers/nine-tails-fox/generated/like"
+	moderationProto "github.com/forum-gamers/nine-tails-fox/generated/moderation"
 	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	preferenceProto "github.com/forum-gamers/nine-tails-fox/generated/preference"
+	relationProto "github.com/forum-gamers/nine-tails-fox/generated/relation"
 	replyProto "github.com/forum-gamers/nine-tails-fox/generated/reply"
-	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	"github.com/forum-gamers/nine-tails-fox/health"
 	"github.com/forum-gamers/nine-tails-fox/interceptors"
+	"github.com/forum-gamers/nine-tails-fox/jobs"
+	"github.com/forum-gamers/nine-tails-fox/metrics"
+	"github.com/forum-gamers/nine-tails-fox/migrations"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
 	"github.com/forum-gamers/nine-tails-fox/pkg/bookmark"
 	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
+	"github.com/forum-gamers/nine-tails-fox/pkg/idempotency"
 	"github.com/forum-gamers/nine-tails-fox/pkg/like"
+	"github.com/forum-gamers/nine-tails-fox/pkg/lock"
+	"github.com/forum-gamers/nine-tails-fox/pkg/media"
+	"github.com/forum-gamers/nine-tails-fox/pkg/moderation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/poll"
 	"github.com/forum-gamers/nine-tails-fox/pkg/post"
 	"github.com/forum-gamers/nine-tails-fox/pkg/preference"
+	"github.com/forum-gamers/nine-tails-fox/pkg/preview"
+	"github.com/forum-gamers/nine-tails-fox/pkg/relation"
 	"github.com/forum-gamers/nine-tails-fox/pkg/reply"
 	"github.com/forum-gamers/nine-tails-fox/pkg/share"
+	"github.com/forum-gamers/nine-tails-fox/pkg/spam"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
 	"github.com/forum-gamers/nine-tails-fox/utils"
-	"github.com/joho/godotenv"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
-	h.PanicIfError(godotenv.Load())
-	database.Connection()
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Failed to load config : %s", err.Error())
+	}
 
-	address := os.Getenv("PORT")
-	if address == "" {
-		address = "50052"
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracer, err := tracing.NewProvider(context.Background(), tracing.Exporter(cfg.Tracing.Exporter), cfg.Tracing.ServiceName)
+	if err != nil {
+		log.Fatalf("Failed to start tracer : %s", err.Error())
 	}
+	defer shutdownTracer(context.Background())
 
-	lis, err := net.Listen("tcp", ":"+address)
+	db, err := database.NewDatabase(ctx, cfg.Database)
+	if err != nil {
+		log.Fatalf("Failed to connect database : %s", err.Error())
+	}
+
+	if cfg.Migrations.OnStartup || cfg.Migrations.Only {
+		records, err := migrations.NewMigrator(db, migrations.All...).Apply(ctx)
+		if err != nil {
+			log.Fatalf("Failed to apply migrations : %s", err.Error())
+		}
+		log.Printf("Applied %d migrations", len(records))
+
+		if cfg.Migrations.Only {
+			db.Disconnect(context.Background())
+			return
+		}
+	}
+
+	lis, err := net.Listen("tcp", ":"+cfg.Port)
 	if err != nil {
 		log.Fatalf("Failed to listen : %s", err.Error())
 	}
@@ -43,61 +92,230 @@ func main() {
 	query := utils.NewQueryUtils()
 
 	//repository
-	postRepo := post.NewPostRepo(query)
-	likeRepo := like.NewLikeRepo(query)
-	commentRepo := comment.NewCommentRepo(query)
-	shareRepo := share.NewShareRepo()
-	userPreferenceRepo := preference.NewPreferenceRepo()
-	bookmarkRepo := bookmark.NewBookMarkRepo(query)
+	postRepo := post.NewPostRepo(db, query)
+	likeRepo := like.NewLikeRepo(db, query)
+	commentRepo := comment.NewCommentRepo(db, query)
+	shareRepo := share.NewShareRepo(db)
+	userPreferenceRepo := preference.NewPreferenceRepo(db)
+	bookmarkRepo := bookmark.NewBookMarkRepo(db, query)
+	idempotencyRepo := idempotency.NewIdempotencyRepo(db)
+	pollRepo := poll.NewPollRepo(db, query)
+	reportRepo := moderation.NewReportRepo(db, query)
+	actionRepo := moderation.NewActionRepo(db)
+	warningRepo := moderation.NewWarningRepo(db)
+	auditRepo := audit.NewAuditRepo(db, query)
+	fingerprintRepo := spam.NewFingerprintRepo(db)
+	relationRepo := relation.NewRelationRepo(db)
+	linkPreviewRepo := preview.NewPreviewRepo(db)
+	lockRepo := lock.NewLockRepo(db)
+	mediaCleanupRepo := media.NewCleanupRepo(db)
 
 	//services
 	postService := post.NewPostService(postRepo)
-	userPreferenceService := preference.NewPreferenceService(userPreferenceRepo)
+	userPreferenceService := preference.NewPreferenceService(userPreferenceRepo, cfg.Preference.HalfLife)
 	commentService := comment.NewCommentService(commentRepo)
 	bookmarkService := bookmark.NewBookMarkService(bookmarkRepo)
 	replyService := reply.NewReplyService(commentRepo)
+	pollService := poll.NewPollService(pollRepo)
+	moderationService := moderation.NewModerationService()
+	relationService := relation.NewRelationService()
+	mediaCleanupService := media.NewCleanupService(mediaCleanupRepo)
+	linkPreviewService := preview.NewPreviewService(linkPreviewRepo, preview.NewHTTPFetcher(preview.Options{
+		Timeout:  cfg.LinkPreview.Timeout,
+		MaxBytes: cfg.LinkPreview.MaxBytes,
+	}), cfg.LinkPreview.MaxLinks, cfg.LinkPreview.CacheTTL, cfg.LinkPreview.FailureTTL)
+	spamService := spam.NewSpamService(fingerprintRepo, spam.Limits{
+		Window:          cfg.Spam.Window,
+		MaxVelocity:     cfg.Spam.MaxVelocity,
+		QuarantineScore: cfg.Spam.QuarantineScore,
+		RejectScore:     cfg.Spam.RejectScore,
+	})
+
+	contentFilter, err := filter.NewContentFilter(cfg.ContentFilter.RulesFile)
+	if err != nil {
+		log.Fatalf("Failed to load content filter : %s", err.Error())
+	}
 
-	interceptor := interceptors.NewInterCeptor()
+	interceptor := interceptors.NewInterCeptor(cfg, idempotencyRepo)
+	auditService := audit.NewAuditService(auditRepo, interceptor.GetRequestIdFromCtx)
 	grpcServer := grpc.NewServer(
-		grpc.ChainUnaryInterceptor(interceptor.Logging, interceptor.UnaryAuthentication),
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(interceptor.RequestId, interceptor.Logging, interceptor.Metrics, interceptor.Recovery, interceptor.UnaryAuthentication, interceptor.Pagination, interceptor.Idempotency),
 	)
 
-	postProto.RegisterPostServiceServer(grpcServer, &cc.PostService{
-		GetUser:     interceptor.GetUserFromCtx,
-		PostRepo:    postRepo,
-		PostService: postService,
-		LikeRepo:    likeRepo,
-		CommentRepo: commentRepo,
-		ShareRepo:   shareRepo,
-	})
+	postController := &cc.PostService{
+		GetUser:        interceptor.GetUserFromCtx,
+		PostRepo:       postRepo,
+		PostService:    postService,
+		LikeRepo:       likeRepo,
+		CommentRepo:    commentRepo,
+		ShareRepo:      shareRepo,
+		PollRepo:       pollRepo,
+		PollService:    pollService,
+		TrashRetention: cfg.Trash.Retention,
+		MaxPins:        cfg.Post.MaxPins,
+		MediaRules: post.MediaRules{
+			MaxPerPost:   cfg.Media.MaxPerPost,
+			MaxSize:      cfg.Media.MaxSize,
+			AllowedTypes: cfg.Media.AllowedTypes,
+			AllowedHosts: cfg.Media.AllowedHosts,
+		},
+		AuditService:      auditService,
+		ContentFilter:     contentFilter,
+		ReportRepo:        reportRepo,
+		ModerationService: moderationService,
+		SpamService:       spamService,
+		RelationRepo:      relationRepo,
+		PreferenceRepo:    userPreferenceRepo,
+		PreviewService:    linkPreviewService,
+		LockRepo:          lockRepo,
+	}
+	postProto.RegisterPostServiceServer(grpcServer, postController)
 	likeProto.RegisterLikeServiceServer(grpcServer, &cc.LikeService{
 		GetUser:               interceptor.GetUserFromCtx,
 		LikeRepo:              likeRepo,
 		PostRepo:              postRepo,
 		UserPreferenceRepo:    userPreferenceRepo,
 		UserPreferenceService: userPreferenceService,
+		TrackPreference:       cfg.Features.PreferenceTracking,
+		AuditService:          auditService,
+		RelationRepo:          relationRepo,
 	})
 	commentProto.RegisterCommentServiceServer(grpcServer, &cc.CommentService{
-		GetUser:        interceptor.GetUserFromCtx,
-		PostRepo:       postRepo,
-		CommentRepo:    commentRepo,
-		CommentService: commentService,
+		GetUser:           interceptor.GetUserFromCtx,
+		PostRepo:          postRepo,
+		CommentRepo:       commentRepo,
+		CommentService:    commentService,
+		AuditService:      auditService,
+		ContentFilter:     contentFilter,
+		ReportRepo:        reportRepo,
+		ModerationService: moderationService,
+		SpamService:       spamService,
+		RelationRepo:      relationRepo,
 	})
 	bookmarkProto.RegisterBookmarkServiceServer(grpcServer, &cc.BookmarkService{
 		GetUser:         interceptor.GetUserFromCtx,
 		PostRepo:        postRepo,
 		BookmarkRepo:    bookmarkRepo,
 		BookmarkService: bookmarkService,
+		AuditService:    auditService,
+		RelationRepo:    relationRepo,
+	})
+	moderationProto.RegisterModerationServiceServer(grpcServer, &cc.ModerationService{
+		GetUser:           interceptor.GetUserFromCtx,
+		PostRepo:          postRepo,
+		CommentRepo:       commentRepo,
+		ReportRepo:        reportRepo,
+		ActionRepo:        actionRepo,
+		WarningRepo:       warningRepo,
+		ModerationService: moderationService,
+		AuditService:      auditService,
+	})
+	auditProto.RegisterAuditServiceServer(grpcServer, &cc.AuditService{
+		GetUser:   interceptor.GetUserFromCtx,
+		AuditRepo: auditRepo,
 	})
 	replyProto.RegisterReplyServiceServer(grpcServer, &cc.ReplyService{
-		GetUser:        interceptor.GetUserFromCtx,
-		CommentRepo:    commentRepo,
-		CommentService: commentService,
-		ReplyService:   replyService,
+		GetUser:           interceptor.GetUserFromCtx,
+		PostRepo:          postRepo,
+		CommentRepo:       commentRepo,
+		CommentService:    commentService,
+		ReplyService:      replyService,
+		AuditService:      auditService,
+		ContentFilter:     contentFilter,
+		ReportRepo:        reportRepo,
+		ModerationService: moderationService,
+		SpamService:       spamService,
+		RelationRepo:      relationRepo,
 	})
+	relationProto.RegisterRelationServiceServer(grpcServer, &cc.RelationService{
+		GetUser:         interceptor.GetUserFromCtx,
+		PostRepo:        postRepo,
+		RelationRepo:    relationRepo,
+		RelationService: relationService,
+		AuditService:    auditService,
+	})
+	preferenceProto.RegisterPreferenceServiceServer(grpcServer, &cc.PreferenceService{
+		GetUser:               interceptor.GetUserFromCtx,
+		PostRepo:              postRepo,
+		UserPreferenceRepo:    userPreferenceRepo,
+		UserPreferenceService: userPreferenceService,
+		AuditService:          auditService,
+	})
+
+	healthChecker := health.NewHealthChecker(db, cfg.Health.Interval,
+		postProto.PostService_ServiceDesc.ServiceName,
+		likeProto.LikeService_ServiceDesc.ServiceName,
+		commentProto.CommentService_ServiceDesc.ServiceName,
+		bookmarkProto.BookmarkService_ServiceDesc.ServiceName,
+		replyProto.ReplyService_ServiceDesc.ServiceName,
+		moderationProto.ModerationService_ServiceDesc.ServiceName,
+		auditProto.AuditService_ServiceDesc.ServiceName,
+		relationProto.RelationService_ServiceDesc.ServiceName,
+		preferenceProto.PreferenceService_ServiceDesc.ServiceName,
+	)
+	healthpb.RegisterHealthServer(grpcServer, healthChecker.Server())
+	go healthChecker.Run(ctx)
+
+	go jobs.NewRunner(
+		jobs.NewCounterReconciler(postRepo, cfg.Jobs.CounterReconcileInterval),
+		jobs.NewTrashPurger(postRepo, likeRepo, commentRepo, shareRepo, pollRepo, auditService, cfg.Trash.Retention, cfg.Jobs.TrashPurgeInterval, mediaCleanupService.Enqueue),
+		jobs.NewPostScheduler(postRepo, cfg.Jobs.PostSchedulerInterval, postController.PublishScheduled),
+		jobs.NewContentFilterReloader(contentFilter, cfg.Jobs.ContentFilterReload),
+	).Run(ctx)
+
+	if cfg.Features.Reflection {
+		reflection.Register(grpcServer)
+	}
 
-	log.Printf("Starting to serve in port : %s", address)
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve : %s", err.Error())
+	var metricsServer *http.Server
+	if cfg.Features.Metrics {
+		metricsServer = metrics.NewServer(cfg.Metrics.Port)
+		go func() {
+			log.Printf("Serving metrics in port : %s", cfg.Metrics.Port)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Failed to serve metrics : %s", err.Error())
+			}
+		}()
 	}
+
+	go func() {
+		log.Printf("Starting to serve in port : %s", cfg.Port)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("Failed to serve : %s", err.Error())
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	healthChecker.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-shutdownCtx.Done():
+		log.Println("Graceful shutdown timed out, forcing stop")
+		grpcServer.Stop()
+	}
+
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Failed to stop metrics server : %s", err.Error())
+		}
+	}
+
+	if err := db.Disconnect(shutdownCtx); err != nil {
+		log.Printf("Failed to disconnect database : %s", err.Error())
+	}
+
+	log.Println("Server stopped")
 }
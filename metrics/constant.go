@@ -0,0 +1,92 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+const NAMESPACE = "nine_tails_fox"
+
+var (
+	RequestTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "Total gRPC requests handled, by method and status code",
+	}, []string{"method", "code"})
+
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "gRPC request latency, by method and status code",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	MongoCommandDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "mongo",
+		Name:      "command_duration_seconds",
+		Help:      "MongoDB command latency, by command name and outcome",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"command", "status"})
+
+	MongoPoolConnections = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "mongo",
+		Name:      "pool_connections",
+		Help:      "MongoDB pool connections, by server address and state (open, in_use)",
+	}, []string{"address", "state"})
+
+	MongoPoolEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Subsystem: "mongo",
+		Name:      "pool_events_total",
+		Help:      "MongoDB pool events, by server address and event type",
+	}, []string{"address", "type"})
+
+	PostsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "posts_created_total",
+		Help:      "Total posts created",
+	})
+
+	PostsDeleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "posts_deleted_total",
+		Help:      "Total posts deleted",
+	})
+
+	LikesCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "likes_created_total",
+		Help:      "Total likes created",
+	})
+
+	LikesDeleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "likes_deleted_total",
+		Help:      "Total likes removed",
+	})
+
+	CommentsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "comments_created_total",
+		Help:      "Total comments created",
+	})
+
+	RepliesCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "replies_created_total",
+		Help:      "Total replies created",
+	})
+
+	BookmarksCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "bookmarks_created_total",
+		Help:      "Total bookmarks created",
+	})
+
+	SpamVerdicts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: NAMESPACE,
+		Name:      "spam_verdicts_total",
+		Help:      "Spam checks on posted content, by target type and outcome",
+	}, []string{"target", "outcome"})
+)
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func NewCommandMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			MongoCommandDuration.WithLabelValues(e.CommandName, "success").Observe(e.Duration.Seconds())
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			MongoCommandDuration.WithLabelValues(e.CommandName, "failed").Observe(e.Duration.Seconds())
+		},
+	}
+}
+
+func NewPoolMonitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			MongoPoolEvents.WithLabelValues(e.Address, e.Type).Inc()
+
+			switch e.Type {
+			case event.ConnectionCreated:
+				MongoPoolConnections.WithLabelValues(e.Address, "open").Inc()
+			case event.ConnectionClosed:
+				MongoPoolConnections.WithLabelValues(e.Address, "open").Dec()
+			case event.GetSucceeded:
+				MongoPoolConnections.WithLabelValues(e.Address, "in_use").Inc()
+			case event.ConnectionReturned:
+				MongoPoolConnections.WithLabelValues(e.Address, "in_use").Dec()
+			default:
+				break
+			}
+		},
+	}
+}
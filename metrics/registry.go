@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func init() {
+	prometheus.MustRegister(
+		RequestTotal,
+		RequestDuration,
+		MongoCommandDuration,
+		MongoPoolConnections,
+		MongoPoolEvents,
+		PostsCreated,
+		PostsDeleted,
+		LikesCreated,
+		LikesDeleted,
+		CommentsCreated,
+		RepliesCreated,
+		BookmarksCreated,
+		SpamVerdicts,
+	)
+}
+
+func NewServer(address string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	return &http.Server{Addr: ":" + address, Handler: mux}
+}
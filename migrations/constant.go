@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, db database.Database) error
+}
+
+type MigrationRecord struct {
+	Id        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Version   int                `json:"version" bson:"version"`
+	Name      string             `json:"name" bson:"name"`
+	AppliedAt time.Time          `json:"appliedAt" bson:"appliedAt"`
+}
+
+type Migrator interface {
+	Pending(ctx context.Context) ([]Migration, error)
+	Apply(ctx context.Context) ([]MigrationRecord, error)
+}
+
+type MigratorImpl struct {
+	db         database.Database
+	migrations []Migration
+}
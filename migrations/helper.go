@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func createIndexes(name base.CollectionName, models ...mongo.IndexModel) func(ctx context.Context, db database.Database) error {
+	return func(ctx context.Context, db database.Database) error {
+		_, err := base.GetCollection(db, name).Indexes().CreateMany(ctx, models)
+		return err
+	}
+}
+
+func removeDuplicates(ctx context.Context, db database.Database, name base.CollectionName, fields ...string) error {
+	group := bson.D{}
+	for _, field := range fields {
+		group = append(group, bson.E{Key: field, Value: "$" + field})
+	}
+
+	collection := base.GetCollection(db, name)
+	cursor, err := collection.Aggregate(ctx, bson.A{
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: group},
+			{Key: "ids", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}},
+		bson.D{{Key: "$match", Value: bson.D{{Key: "count", Value: bson.D{{Key: "$gt", Value: 1}}}}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var duplicates []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var data struct {
+			Ids []primitive.ObjectID `bson:"ids"`
+		}
+		if err := cursor.Decode(&data); err != nil {
+			return err
+		}
+		duplicates = append(duplicates, data.Ids[1:]...)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+
+	if len(duplicates) > 0 {
+		_, err = collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": duplicates}})
+	}
+	return err
+}
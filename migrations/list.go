@@ -0,0 +1,332 @@
+package migrations
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/pkg/moderation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/preference"
+	"github.com/forum-gamers/nine-tails-fox/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var All = []Migration{
+	{
+		Version: 1,
+		Name:    "create post indexes",
+		Up: createIndexes(base.Post,
+			mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "privacy", Value: 1}, {Key: "createdAt", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "tags", Value: 1}, {Key: "createdAt", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "createdAt", Value: -1}}},
+		),
+	},
+	{
+		Version: 2,
+		Name:    "create unique like index",
+		Up: func(ctx context.Context, db database.Database) error {
+			if err := removeDuplicates(ctx, db, base.Like, "userId", "postId"); err != nil {
+				return err
+			}
+
+			return createIndexes(base.Like,
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "postId", Value: 1}},
+					Options: options.Index().SetUnique(true),
+				},
+				mongo.IndexModel{Keys: bson.D{{Key: "postId", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+			)(ctx, db)
+		},
+	},
+	{
+		Version: 3,
+		Name:    "create unique bookmark index",
+		Up: func(ctx context.Context, db database.Database) error {
+			if err := removeDuplicates(ctx, db, base.Bookmark, "userId", "postId"); err != nil {
+				return err
+			}
+
+			return createIndexes(base.Bookmark,
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "postId", Value: 1}},
+					Options: options.Index().SetUnique(true),
+				},
+				mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: 1}}},
+			)(ctx, db)
+		},
+	},
+	{
+		Version: 4,
+		Name:    "create comment indexes",
+		Up: createIndexes(base.Comment,
+			mongo.IndexModel{Keys: bson.D{{Key: "postId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		),
+	},
+	{
+		Version: 5,
+		Name:    "create share indexes",
+		Up: createIndexes(base.Share,
+			mongo.IndexModel{Keys: bson.D{{Key: "postId", Value: 1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "postId", Value: 1}}},
+		),
+	},
+	{
+		Version: 6,
+		Name:    "create unique preference index",
+		Up: func(ctx context.Context, db database.Database) error {
+			if err := removeDuplicates(ctx, db, base.Preference, "userId"); err != nil {
+				return err
+			}
+
+			return createIndexes(base.Preference,
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "userId", Value: 1}},
+					Options: options.Index().SetUnique(true),
+				},
+			)(ctx, db)
+		},
+	},
+	{
+		Version: 7,
+		Name:    "create idempotency indexes",
+		Up: createIndexes(base.Idempotency,
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "key", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(0),
+			},
+		),
+	},
+	{
+		Version: 8,
+		Name:    "backfill post counters",
+		Up: func(ctx context.Context, db database.Database) error {
+			_, err := post.NewPostRepo(db, utils.NewQueryUtils()).ReconcileCounters(ctx)
+			return err
+		},
+	},
+	{
+		Version: 9,
+		Name:    "create soft delete indexes",
+		Up: func(ctx context.Context, db database.Database) error {
+			if err := createIndexes(base.Post,
+				mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "deletedAt", Value: -1}}, Options: options.Index().SetSparse(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "deletedAt", Value: 1}}, Options: options.Index().SetSparse(true)},
+			)(ctx, db); err != nil {
+				return err
+			}
+
+			return createIndexes(base.Comment,
+				mongo.IndexModel{Keys: bson.D{{Key: "deletedAt", Value: 1}}, Options: options.Index().SetSparse(true)},
+				mongo.IndexModel{Keys: bson.D{{Key: "reply.deletedAt", Value: 1}}, Options: options.Index().SetSparse(true)},
+			)(ctx, db)
+		},
+	},
+	{
+		Version: 10,
+		Name:    "create draft and schedule indexes",
+		Up: createIndexes(base.Post,
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "status", Value: 1}, {Key: "publishAt", Value: 1}},
+				Options: options.Index().SetPartialFilterExpression(bson.D{{Key: "status", Value: post.Scheduled}}),
+			},
+			mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "status", Value: 1}, {Key: "updatedAt", Value: -1}}},
+		),
+	},
+	{
+		Version: 11,
+		Name:    "create pinned post index",
+		Up: createIndexes(base.Post,
+			mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "pinnedAt", Value: -1}, {Key: "createdAt", Value: -1}}},
+		),
+	},
+	{
+		Version: 12,
+		Name:    "create poll vote index",
+		Up: createIndexes(base.PollVote,
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "postId", Value: 1}, {Key: "userId", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		),
+	},
+	{
+		Version: 13,
+		Name:    "create moderation indexes",
+		Up: func(ctx context.Context, db database.Database) error {
+			if err := createIndexes(base.Report,
+				mongo.IndexModel{
+					Keys:    bson.D{{Key: "targetType", Value: 1}, {Key: "targetId", Value: 1}, {Key: "reporterId", Value: 1}},
+					Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.D{{Key: "status", Value: moderation.Open}}),
+				},
+				mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "targetType", Value: 1}, {Key: "reason", Value: 1}}},
+			)(ctx, db); err != nil {
+				return err
+			}
+
+			return createIndexes(base.ModerationAction,
+				mongo.IndexModel{Keys: bson.D{{Key: "targetType", Value: 1}, {Key: "targetId", Value: 1}}},
+				mongo.IndexModel{Keys: bson.D{{Key: "authorId", Value: 1}, {Key: "createdAt", Value: -1}}},
+			)(ctx, db)
+		},
+	},
+	{
+		Version: 14,
+		Name:    "create audit log indexes",
+		Up: createIndexes(base.Log,
+			mongo.IndexModel{Keys: bson.D{{Key: "createdAt", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "actorId", Value: 1}, {Key: "createdAt", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "action", Value: 1}, {Key: "createdAt", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "targetType", Value: 1}, {Key: "targetId", Value: 1}, {Key: "createdAt", Value: -1}}},
+		),
+	},
+	{
+		Version: 15,
+		Name:    "create content fingerprint indexes",
+		Up: createIndexes(base.Fingerprint,
+			mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "hash", Value: 1}, {Key: "createdAt", Value: -1}}},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(0),
+			},
+		),
+	},
+	{
+		Version: 16,
+		Name:    "create user relation indexes",
+		Up: createIndexes(base.Relation,
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "userId", Value: 1}, {Key: "targetId", Value: 1}, {Key: "type", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			mongo.IndexModel{Keys: bson.D{{Key: "targetId", Value: 1}, {Key: "type", Value: 1}}},
+		),
+	},
+	{
+		Version: 17,
+		Name:    "backfill tag preference weights",
+		Up: func(ctx context.Context, db database.Database) error {
+			weighted := bson.D{{Key: "$map", Value: bson.D{
+				{Key: "input", Value: "$tags"},
+				{Key: "as", Value: "tag"},
+				{Key: "in", Value: bson.D{
+					{Key: "value", Value: "$$tag.value"},
+					{Key: "weight", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$$tag.weight", preference.LIKEWEIGHT}}}},
+					{Key: "createdAt", Value: "$$tag.createdAt"},
+					{Key: "updatedAt", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$$tag.updatedAt", "$$tag.createdAt"}}}},
+				}},
+			}}}
+
+			collection := base.GetCollection(db, base.Preference)
+			if _, err := collection.UpdateMany(ctx,
+				bson.M{"tags": bson.M{"$elemMatch": bson.M{"weight": bson.M{"$exists": false}}}},
+				bson.A{bson.D{{Key: "$set", Value: bson.D{{Key: "tags", Value: weighted}}}}},
+			); err != nil {
+				return err
+			}
+
+			_, err := collection.UpdateMany(ctx, bson.M{"mutedTags": bson.M{"$exists": false}}, bson.M{"$set": bson.M{"mutedTags": bson.A{}}})
+			return err
+		},
+	},
+	{
+		Version: 18,
+		Name:    "create link preview indexes",
+		Up: createIndexes(base.LinkPreview,
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "url", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(0),
+			},
+		),
+	},
+	{
+		Version: 19,
+		Name:    "create media cleanup indexes",
+		Up: createIndexes(base.MediaCleanup,
+			mongo.IndexModel{Keys: bson.D{{Key: "createdAt", Value: 1}}},
+		),
+	},
+	{
+		Version: 20,
+		Name:    "create lock indexes",
+		Up: createIndexes(base.Lock,
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "name", Value: 1}, {Key: "userId", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		),
+	},
+	{
+		Version: 21,
+		Name:    "create user warning indexes",
+		Up: createIndexes(base.Warning,
+			mongo.IndexModel{
+				Keys:    bson.D{{Key: "userId", Value: 1}},
+				Options: options.Index().SetUnique(true),
+			},
+		),
+	},
+	{
+		Version: 22,
+		Name:    "lower case post tags and preference tags",
+		Up: func(ctx context.Context, db database.Database) error {
+			lower := func(field string) bson.D {
+				return bson.D{{Key: "$map", Value: bson.D{
+					{Key: "input", Value: "$" + field},
+					{Key: "in", Value: bson.D{{Key: "$toLower", Value: "$$this"}}},
+				}}}
+			}
+
+			if _, err := base.GetCollection(db, base.Post).UpdateMany(ctx,
+				bson.M{"tags": bson.M{"$regex": "[A-Z]"}},
+				bson.A{bson.D{{Key: "$set", Value: bson.D{{Key: "tags", Value: lower("tags")}}}}},
+			); err != nil {
+				return err
+			}
+
+			// muted tags that only differed in case collapse into one
+			if _, err := base.GetCollection(db, base.Preference).UpdateMany(ctx,
+				bson.M{"mutedTags": bson.M{"$regex": "[A-Z]"}},
+				bson.A{bson.D{{Key: "$set", Value: bson.D{{Key: "mutedTags", Value: bson.D{{Key: "$setUnion", Value: bson.A{lower("mutedTags")}}}}}}}},
+			); err != nil {
+				return err
+			}
+
+			// weighted tags keep the first entry when several collapse into one
+			lowerValues := bson.D{{Key: "$map", Value: bson.D{
+				{Key: "input", Value: "$tags"},
+				{Key: "in", Value: bson.D{{Key: "$mergeObjects", Value: bson.A{
+					"$$this",
+					bson.D{{Key: "value", Value: bson.D{{Key: "$toLower", Value: "$$this.value"}}}},
+				}}}},
+			}}}
+			unique := bson.D{{Key: "$reduce", Value: bson.D{
+				{Key: "input", Value: lowerValues},
+				{Key: "initialValue", Value: bson.A{}},
+				{Key: "in", Value: bson.D{{Key: "$cond", Value: bson.A{
+					bson.D{{Key: "$in", Value: bson.A{"$$this.value", "$$value.value"}}},
+					"$$value",
+					bson.D{{Key: "$concatArrays", Value: bson.A{"$$value", bson.A{"$$this"}}}},
+				}}}},
+			}}}
+			_, err := base.GetCollection(db, base.Preference).UpdateMany(ctx,
+				bson.M{"tags.value": bson.M{"$regex": "[A-Z]"}},
+				bson.A{bson.D{{Key: "$set", Value: bson.D{{Key: "tags", Value: unique}}}}},
+			)
+			return err
+		},
+	},
+}
@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func NewMigrator(db database.Database, migrations ...Migration) Migrator {
+	sorted := append([]Migration{}, migrations...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	return &MigratorImpl{db, sorted}
+}
+
+func (m *MigratorImpl) collection() *mongo.Collection {
+	return base.GetCollection(m.db, base.Migration)
+}
+
+func (m *MigratorImpl) applied(ctx context.Context) (map[int]bool, error) {
+	cursor, err := m.collection().Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	result := map[int]bool{}
+	for cursor.Next(ctx) {
+		var record MigrationRecord
+		if err := cursor.Decode(&record); err != nil {
+			return nil, err
+		}
+		result[record.Version] = true
+	}
+	return result, cursor.Err()
+}
+
+func (m *MigratorImpl) Pending(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+func (m *MigratorImpl) Apply(ctx context.Context) ([]MigrationRecord, error) {
+	if _, err := m.collection().Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "version", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to prepare migration collection : %w", err)
+	}
+
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []MigrationRecord
+	for _, migration := range pending {
+		log.Printf("Applying migration %d : %s", migration.Version, migration.Name)
+		if err := migration.Up(ctx, m.db); err != nil {
+			return records, fmt.Errorf("migration %d (%s) failed : %w", migration.Version, migration.Name, err)
+		}
+
+		record := MigrationRecord{
+			Version:   migration.Version,
+			Name:      migration.Name,
+			AppliedAt: time.Now(),
+		}
+		if _, err := m.collection().InsertOne(ctx, record); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				continue
+			}
+			return records, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/pkg/user"
+	"github.com/forum-gamers/nine-tails-fox/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ActionDeletePost      = "DeletePost"
+	ActionRestorePost     = "RestorePost"
+	ActionPurgePost       = "PurgePost"
+	ActionDeleteComment   = "DeleteComment"
+	ActionDeleteReply     = "DeleteReply"
+	ActionDeleteLike      = "DeleteLike"
+	ActionDeleteBookmark  = "DeleteBookmark"
+	ActionDeleteRelation  = "DeleteRelation"
+	ActionRemoveTag       = "RemoveTag"
+	ActionResetPreference = "ResetPreference"
+	// moderation decisions are recorded as ActionModeratePrefix + action,
+	// e.g. ModerateHide
+	ActionModeratePrefix = "Moderate"
+)
+
+const (
+	TargetPost       = "Post"
+	TargetComment    = "Comment"
+	TargetReply      = "Reply"
+	TargetLike       = "Like"
+	TargetBookmark   = "Bookmark"
+	TargetRelation   = "Relation"
+	TargetPreference = "Preference"
+)
+
+// System is the actor recorded for background jobs.
+var System = user.User{Id: "system", AccountType: "System"}
+
+type AuditRepo interface {
+	Create(ctx context.Context, data *Entry) error
+	Query(ctx context.Context, query Query) ([]Entry, error)
+}
+
+type AuditRepoImpl struct {
+	base.BaseRepo
+	utils.QueryUtils
+}
+
+type AuditService interface {
+	Record(ctx context.Context, actor user.User, action, targetType string, targetId primitive.ObjectID, before, after any) error
+}
+
+type AuditServiceImpl struct {
+	AuditRepo    AuditRepo
+	GetRequestId func(ctx context.Context) string
+}
@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Entry is a single audit log record. Before and After hold the target as it
+// was stored around the mutation, either may be nil for creations or hard
+// deletes.
+type Entry struct {
+	Id         primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	ActorId    string             `json:"actorId" bson:"actorId"`
+	ActorType  string             `json:"actorType" bson:"actorType"`
+	Action     string             `json:"action" bson:"action"`
+	TargetType string             `json:"targetType" bson:"targetType"`
+	TargetId   primitive.ObjectID `json:"targetId" bson:"targetId"`
+	Before     any                `json:"before,omitempty" bson:"before,omitempty"`
+	After      any                `json:"after,omitempty" bson:"after,omitempty"`
+	RequestId  string             `json:"requestId" bson:"requestId"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	TotalData  int                `json:"totalData" bson:"totalData,omitempty"`
+}
+
+type Query struct {
+	Page       int
+	Limit      int
+	ActorId    string
+	Action     string
+	TargetType string
+	TargetId   primitive.ObjectID
+	From       *time.Time
+	To         *time.Time
+}
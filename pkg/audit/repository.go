@@ -0,0 +1,123 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
+	"github.com/forum-gamers/nine-tails-fox/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc/codes"
+)
+
+func NewAuditRepo(db database.Database, q utils.QueryUtils) AuditRepo {
+	return &AuditRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Log)), q}
+}
+
+func (r *AuditRepoImpl) Create(ctx context.Context, data *Entry) error {
+	ctx, span := tracing.Start(ctx, "AuditRepo.Create")
+	defer span.End()
+
+	id, err := r.BaseRepo.Create(ctx, data)
+	if err != nil {
+		return err
+	}
+	data.Id = id
+	return nil
+}
+
+func (r *AuditRepoImpl) Query(ctx context.Context, query Query) ([]Entry, error) {
+	ctx, span := tracing.Start(ctx, "AuditRepo.Query")
+	defer span.End()
+
+	match := bson.D{}
+	if query.ActorId != "" {
+		match = append(match, bson.E{Key: "actorId", Value: query.ActorId})
+	}
+
+	if query.Action != "" {
+		match = append(match, bson.E{Key: "action", Value: query.Action})
+	}
+
+	if query.TargetType != "" {
+		match = append(match, bson.E{Key: "targetType", Value: query.TargetType})
+	}
+
+	if !query.TargetId.IsZero() {
+		match = append(match, bson.E{Key: "targetId", Value: query.TargetId})
+	}
+
+	createdAt := bson.D{}
+	if query.From != nil {
+		createdAt = append(createdAt, bson.E{Key: "$gte", Value: *query.From})
+	}
+
+	if query.To != nil {
+		createdAt = append(createdAt, bson.E{Key: "$lt", Value: *query.To})
+	}
+
+	if len(createdAt) > 0 {
+		match = append(match, bson.E{Key: "createdAt", Value: createdAt})
+	}
+
+	curr, err := r.Aggregations(ctx, bson.A{
+		bson.D{{Key: "$match", Value: match}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}}},
+		bson.D{
+			{Key: "$facet",
+				Value: bson.D{
+					{Key: "total",
+						Value: bson.A{
+							bson.D{{Key: "$count", Value: "total"}},
+						},
+					},
+					{Key: "datas",
+						Value: bson.A{
+							r.NewSkip((query.Page - 1) * query.Limit),
+							r.NewLimit(query.Limit),
+						},
+					},
+				},
+			},
+		},
+		r.NewRawUnwind("$datas"),
+		r.NewRawUnwind("$total"),
+		bson.D{
+			{Key: "$project",
+				Value: bson.D{
+					{Key: "_id", Value: "$datas._id"},
+					{Key: "actorId", Value: "$datas.actorId"},
+					{Key: "actorType", Value: "$datas.actorType"},
+					{Key: "action", Value: "$datas.action"},
+					{Key: "targetType", Value: "$datas.targetType"},
+					{Key: "targetId", Value: "$datas.targetId"},
+					{Key: "before", Value: "$datas.before"},
+					{Key: "after", Value: "$datas.after"},
+					{Key: "requestId", Value: "$datas.requestId"},
+					{Key: "createdAt", Value: "$datas.createdAt"},
+					{Key: "totalData", Value: "$total.total"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	var datas []Entry
+	for curr.Next(ctx) {
+		var data Entry
+		if err := curr.Decode(&data); err != nil {
+			return nil, err
+		}
+		datas = append(datas, data)
+	}
+
+	if len(datas) < 1 {
+		return datas, h.NewAppError(codes.NotFound, "data not found")
+	}
+	return datas, nil
+}
@@ -0,0 +1,27 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewAuditService(repo AuditRepo, getRequestId func(ctx context.Context) string) AuditService {
+	return &AuditServiceImpl{repo, getRequestId}
+}
+
+func (s *AuditServiceImpl) Record(ctx context.Context, actor user.User, action, targetType string, targetId primitive.ObjectID, before, after any) error {
+	return s.AuditRepo.Create(ctx, &Entry{
+		ActorId:    actor.Id,
+		ActorType:  actor.AccountType,
+		Action:     action,
+		TargetType: targetType,
+		TargetId:   targetId,
+		Before:     before,
+		After:      after,
+		RequestId:  s.GetRequestId(ctx),
+		CreatedAt:  time.Now(),
+	})
+}
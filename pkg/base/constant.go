@@ -10,14 +10,25 @@ import (
 type CollectionName string
 
 const (
-	Post       CollectionName = "post"
-	Like       CollectionName = "like"
-	Comment    CollectionName = "comment"
-	Reply      CollectionName = "replyComment"
-	Share      CollectionName = "share"
-	Log        CollectionName = "log"
-	Bookmark   CollectionName = "bookmark"
-	Preference CollectionName = "preference"
+	Post             CollectionName = "post"
+	Like             CollectionName = "like"
+	Comment          CollectionName = "comment"
+	Reply            CollectionName = "replyComment"
+	Share            CollectionName = "share"
+	Log              CollectionName = "log"
+	Bookmark         CollectionName = "bookmark"
+	Preference       CollectionName = "preference"
+	Migration        CollectionName = "migration"
+	Idempotency      CollectionName = "idempotency"
+	PollVote         CollectionName = "pollVote"
+	Report           CollectionName = "report"
+	ModerationAction CollectionName = "moderationAction"
+	Fingerprint      CollectionName = "contentFingerprint"
+	Relation         CollectionName = "userRelation"
+	LinkPreview      CollectionName = "linkPreview"
+	MediaCleanup     CollectionName = "mediaCleanup"
+	Lock             CollectionName = "lock"
+	Warning          CollectionName = "userWarning"
 )
 
 type BaseRepo interface {
@@ -30,12 +41,17 @@ type BaseRepo interface {
 	FindOneByQuery(ctx context.Context, query any, result any) error
 	UpdateOneByQuery(ctx context.Context, id primitive.ObjectID, query any) (*mongo.UpdateResult, error)
 	UpdateOne(ctx context.Context, filter, update any) (*mongo.UpdateResult, error)
+	UpsertOne(ctx context.Context, filter, update any) (*mongo.UpdateResult, error)
 	FindByQuery(ctx context.Context, query any) (*mongo.Cursor, error)
+	CountDocuments(ctx context.Context, filter any) (int64, error)
 	BulkUpdate(ctx context.Context, updateModel []mongo.WriteModel) (*mongo.BulkWriteResult, error)
 	Aggregations(ctx context.Context, aggregation any) (*mongo.Cursor, error)
 	GetSession() (mongo.Session, error)
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 	UpdateMany(ctx context.Context, filter any, update any) (*mongo.UpdateResult, error)
 	DeleteMany(ctx context.Context, filter any) (*mongo.DeleteResult, error)
+	DeleteOne(ctx context.Context, filter any) (*mongo.DeleteResult, error)
+	InsertIfNotExists(ctx context.Context, filter, data any) (primitive.ObjectID, bool, error)
 }
 
 type BaseRepoImpl struct {
@@ -8,6 +8,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/grpc/codes"
 )
 
@@ -51,13 +52,32 @@ func (r *BaseRepoImpl) InsertMany(ctx context.Context, data []any) (*mongo.Inser
 func (r *BaseRepoImpl) Create(ctx context.Context, data any) (primitive.ObjectID, error) {
 	result, err := r.DB.InsertOne(ctx, data)
 	if err != nil {
-		return primitive.NilObjectID, err
+		return primitive.NilObjectID, ParseWriteError(err)
 	}
 	return result.InsertedID.(primitive.ObjectID), nil
 }
 
-func GetCollection(name CollectionName) *mongo.Collection {
-	return database.DB.Collection(string(name))
+func (r *BaseRepoImpl) InsertIfNotExists(ctx context.Context, filter, data any) (primitive.ObjectID, bool, error) {
+	result, err := r.DB.UpdateOne(ctx, filter, bson.M{"$setOnInsert": data}, options.Update().SetUpsert(true))
+	if err != nil {
+		return primitive.NilObjectID, false, ParseWriteError(err)
+	}
+
+	if result.UpsertedCount < 1 {
+		return primitive.NilObjectID, false, nil
+	}
+	return result.UpsertedID.(primitive.ObjectID), true, nil
+}
+
+func ParseWriteError(err error) error {
+	if mongo.IsDuplicateKeyError(err) {
+		return h.NewAppError(codes.AlreadyExists, "Conflict")
+	}
+	return err
+}
+
+func GetCollection(db database.Database, name CollectionName) *mongo.Collection {
+	return db.Collection(string(name))
 }
 
 func (r *BaseRepoImpl) FindOneByQuery(ctx context.Context, query any, result any) (err error) {
@@ -76,14 +96,39 @@ func (r *BaseRepoImpl) UpdateOne(ctx context.Context, filter, update any) (*mong
 	return r.DB.UpdateOne(ctx, filter, update)
 }
 
+func (r *BaseRepoImpl) UpsertOne(ctx context.Context, filter, update any) (*mongo.UpdateResult, error) {
+	result, err := r.DB.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return result, ParseWriteError(err)
+}
+
 func (r *BaseRepoImpl) FindByQuery(ctx context.Context, query any) (*mongo.Cursor, error) {
 	return r.DB.Find(ctx, query)
 }
 
+func (r *BaseRepoImpl) CountDocuments(ctx context.Context, filter any) (int64, error) {
+	return r.DB.CountDocuments(ctx, filter)
+}
+
 func (r *BaseRepoImpl) GetSession() (mongo.Session, error) {
 	return r.DB.Database().Client().StartSession()
 }
 
+// WithTransaction runs fn inside a transaction on a new session. The driver
+// retries the whole callback on TransientTransactionError and the commit on
+// UnknownTransactionCommitResult, so fn must be safe to run more than once.
+func (r *BaseRepoImpl) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := r.GetSession()
+	if err != nil {
+		return h.NewAppError(codes.Unavailable, "Failed get session")
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (any, error) {
+		return nil, fn(sessCtx)
+	})
+	return err
+}
+
 func (b *BaseRepoImpl) BulkUpdate(ctx context.Context, updateModel []mongo.WriteModel) (*mongo.BulkWriteResult, error) {
 	return b.DB.BulkWrite(ctx, updateModel)
 }
@@ -99,3 +144,7 @@ func (b *BaseRepoImpl) UpdateMany(ctx context.Context, filter any, update any) (
 func (b *BaseRepoImpl) DeleteMany(ctx context.Context, filter any) (*mongo.DeleteResult, error) {
 	return b.DB.DeleteMany(ctx, filter)
 }
+
+func (b *BaseRepoImpl) DeleteOne(ctx context.Context, filter any) (*mongo.DeleteResult, error) {
+	return b.DB.DeleteOne(ctx, filter)
+}
@@ -7,7 +7,7 @@ import (
 )
 
 type Bookmark struct {
-	Id        primitive.ObjectID `json:"_id" bson:"_id"`
+	Id        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
 	PostId    primitive.ObjectID `json:"postId" bson:"postId"`
 	UserId    string             `json:"userId" bson:"userId"`
 	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
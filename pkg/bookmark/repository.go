@@ -3,65 +3,86 @@ package bookmark
 import (
 	"context"
 
+	"github.com/forum-gamers/nine-tails-fox/database"
 	h "github.com/forum-gamers/nine-tails-fox/helpers"
 	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
 	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
 	"github.com/forum-gamers/nine-tails-fox/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/grpc/codes"
 )
 
-func NewBookMarkRepo(q utils.QueryUtils) BookmarkRepo {
-	return &BookmarkRepoImpl{b.NewBaseRepo(b.GetCollection(b.Bookmark)), q}
+func NewBookMarkRepo(db database.Database, q utils.QueryUtils) BookmarkRepo {
+	return &BookmarkRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Bookmark)), q}
 }
 
 func (r *BookmarkRepoImpl) CreateOne(ctx context.Context, data *Bookmark) error {
-	if result, err := r.Create(ctx, data); err != nil {
+	ctx, span := tracing.Start(ctx, "BookmarkRepo.CreateOne")
+	defer span.End()
+
+	id, created, err := r.InsertIfNotExists(ctx, bson.M{"userId": data.UserId, "postId": data.PostId}, data)
+	if err != nil {
 		return err
-	} else {
-		data.Id = result
 	}
+
+	if !created {
+		return h.NewAppError(codes.AlreadyExists, "Conflict")
+	}
+	data.Id = id
 	return nil
 }
 
 func (r *BookmarkRepoImpl) FindOne(ctx context.Context, query any, result *Bookmark) error {
+	ctx, span := tracing.Start(ctx, "BookmarkRepo.FindOne")
+	defer span.End()
+
 	return r.FindOneByQuery(ctx, query, result)
 }
 
 func (r *BookmarkRepoImpl) FindById(ctx context.Context, id primitive.ObjectID, result *Bookmark) error {
+	ctx, span := tracing.Start(ctx, "BookmarkRepo.FindById")
+	defer span.End()
+
 	return r.FindOneById(ctx, id, result)
 }
 
 func (r *BookmarkRepoImpl) DeleteOneById(ctx context.Context, id primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "BookmarkRepo.DeleteOneById")
+	defer span.End()
+
 	return r.BaseRepo.DeleteOneById(ctx, id)
 }
 
 func (r *BookmarkRepoImpl) FindByPostIdAndUserId(ctx context.Context, postId primitive.ObjectID, userId string, result *Bookmark) error {
+	ctx, span := tracing.Start(ctx, "BookmarkRepo.FindByPostIdAndUserId")
+	defer span.End()
+
 	return r.FindOneByQuery(ctx, bson.M{"postId": postId, "userId": userId}, result)
 }
 
 func (r *BookmarkRepoImpl) FindMyBookmarks(ctx context.Context, postId primitive.ObjectID, userId string, query b.Pagination) (result []post.PostResponse, err error) {
+	ctx, span := tracing.Start(ctx, "BookmarkRepo.FindMyBookmarks")
+	defer span.End()
+
 	cursor, err := r.Aggregations(ctx, bson.A{
 		bson.D{{Key: "$match", Value: bson.D{{Key: "userId", Value: userId}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: 1}}}},
+		r.NewLookup("post", "postId", "_id", "post"),
+		r.NewRawUnwind("$post"),
+		bson.D{{Key: "$match", Value: post.VisibleFilter("post.")}},
 		bson.D{
 			{Key: "$facet", Value: bson.D{
 				{Key: "data", Value: bson.A{
-					bson.D{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: 1}}}},
 					r.NewSkip(int(query.Page-1) * int(query.Limit)),
 					r.NewLimit(int(query.Limit)),
-					r.NewLookup("post", "postId", "_id", "post"),
-					r.NewRawUnwind("$post"),
-					r.NewLookup("like", "post._id", "postId", "like"),
-					r.NewLookup("share", "post._id", "postId", "share"),
-					r.NewLookup("comment", "post._id", "postId", "comment"),
+					r.NewUserLookup("like", "post._id", "postId", userId, "like"),
+					r.NewUserLookup("share", "post._id", "postId", userId, "share"),
 					bson.D{
 						{Key: "$addFields", Value: bson.D{
-							{Key: "countLike", Value: bson.D{{Key: "$size", Value: "$like"}}},
-							{Key: "countShare", Value: bson.D{{Key: "$size", Value: "$share"}}},
-							r.NewCountComment("countComment", "$comment"),
-							r.IsDo("isLiked", "$like", userId),
-							r.IsDo("isShared", "$share", userId),
+							r.IsExists("isLiked", "$like"),
+							r.IsExists("isShared", "$share"),
 						},
 						},
 					},
@@ -82,12 +103,14 @@ func (r *BookmarkRepoImpl) FindMyBookmarks(ctx context.Context, postId primitive
 				{Key: "userId", Value: "$data.post.userId"},
 				{Key: "text", Value: "$data.post.text"},
 				{Key: "media", Value: "$data.post.media"},
+				{Key: "poll", Value: "$data.post.poll"},
+				{Key: "previews", Value: "$data.post.previews"},
 				{Key: "allowComment", Value: "$data.post.allowComment"},
 				{Key: "isLiked", Value: "$data.isLiked"},
 				{Key: "isShared", Value: "$data.isShared"},
-				{Key: "countLike", Value: "$data.countLike"},
-				{Key: "countShare", Value: "$data.countShare"},
-				{Key: "countComment", Value: "$data.countComment"},
+				{Key: "countLike", Value: "$data.post.countLike"},
+				{Key: "countShare", Value: "$data.post.countShare"},
+				{Key: "countComment", Value: "$data.post.countComment"},
 				{Key: "tags", Value: "$data.post.tags"},
 				{Key: "privacy", Value: "$data.post.privacy"},
 				{Key: "totalData", Value: "$total.total"},
@@ -2,6 +2,7 @@ package comment
 
 import (
 	"context"
+	"time"
 
 	"github.com/forum-gamers/nine-tails-fox/pkg/base"
 	"github.com/forum-gamers/nine-tails-fox/utils"
@@ -19,7 +20,16 @@ type CommentRepo interface {
 	FindReplyById(ctx context.Context, id, replyId primitive.ObjectID, data *ReplyComment) error
 	DeleteOneReply(ctx context.Context, id, replyId primitive.ObjectID) error
 	DeleteMany(ctx context.Context, postId primitive.ObjectID) error
-	FindPostComment(ctx context.Context, postId primitive.ObjectID, query struct{ Page, Limit int }) ([]CommentResponse, error)
+	SoftDelete(ctx context.Context, id primitive.ObjectID, userId string) (int64, error)
+	SoftDeleteReply(ctx context.Context, id, replyId primitive.ObjectID, userId string) (int64, error)
+	Hide(ctx context.Context, id primitive.ObjectID, userId string) (int64, error)
+	HideReply(ctx context.Context, id, replyId primitive.ObjectID, userId string) (int64, error)
+	FindHiddenById(ctx context.Context, id primitive.ObjectID, data *Comment) error
+	FindHiddenReplyById(ctx context.Context, id, replyId primitive.ObjectID, data *ReplyComment) error
+	Unhide(ctx context.Context, id primitive.ObjectID) (int64, error)
+	UnhideReply(ctx context.Context, id, replyId primitive.ObjectID) (int64, error)
+	PurgeDeleted(ctx context.Context, before time.Time) error
+	FindPostComment(ctx context.Context, postId primitive.ObjectID, excluded []string, query struct{ Page, Limit int }) ([]CommentResponse, error)
 }
 
 type CommentRepoImpl struct {
@@ -0,0 +1,31 @@
+package comment
+
+import "go.mongodb.org/mongo-driver/bson"
+
+// ActiveFilter matches comments or replies that are neither in the trash nor
+// hidden by a moderator. prefix is the path of the comment inside the
+// document, e.g. "reply." after an $unwind.
+func ActiveFilter(prefix string) bson.D {
+	return bson.D{
+		{Key: prefix + "deletedAt", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: prefix + "hiddenAt", Value: bson.D{{Key: "$exists", Value: false}}},
+	}
+}
+
+// ActiveReplyCond is the $filter condition equivalent of ActiveFilter for the
+// reply bound to $$this.
+func ActiveReplyCond() bson.D {
+	return bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$type", Value: "$$this.deletedAt"}}, "missing"}}},
+		bson.D{{Key: "$eq", Value: bson.A{bson.D{{Key: "$type", Value: "$$this.hiddenAt"}}, "missing"}}},
+	}}}
+}
+
+// HiddenFilter matches comments or replies hidden by a moderator or held for
+// review that are not in the trash.
+func HiddenFilter(prefix string) bson.D {
+	return bson.D{
+		{Key: prefix + "deletedAt", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: prefix + "hiddenAt", Value: bson.D{{Key: "$exists", Value: true}}},
+	}
+}
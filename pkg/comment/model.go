@@ -14,6 +14,10 @@ type Comment struct {
 	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
 	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
 	Reply     []ReplyComment     `json:"reply" bson:"reply"`
+	DeletedAt *time.Time         `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	DeletedBy string             `json:"deletedBy,omitempty" bson:"deletedBy,omitempty"`
+	HiddenAt  *time.Time         `json:"hiddenAt,omitempty" bson:"hiddenAt,omitempty"`
+	HiddenBy  string             `json:"hiddenBy,omitempty" bson:"hiddenBy,omitempty"`
 }
 
 type ReplyComment struct {
@@ -22,6 +26,10 @@ type ReplyComment struct {
 	Text      string             `json:"text" bson:"text,omitempty"`
 	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
 	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
+	DeletedAt *time.Time         `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	DeletedBy string             `json:"deletedBy,omitempty" bson:"deletedBy,omitempty"`
+	HiddenAt  *time.Time         `json:"hiddenAt,omitempty" bson:"hiddenAt,omitempty"`
+	HiddenBy  string             `json:"hiddenBy,omitempty" bson:"hiddenBy,omitempty"`
 }
 
 type CommentResponse struct {
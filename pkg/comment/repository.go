@@ -2,9 +2,12 @@ package comment
 
 import (
 	"context"
+	"time"
 
+	"github.com/forum-gamers/nine-tails-fox/database"
 	h "github.com/forum-gamers/nine-tails-fox/helpers"
 	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
 	"github.com/forum-gamers/nine-tails-fox/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,11 +16,14 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-func NewCommentRepo(q utils.QueryUtils) CommentRepo {
-	return &CommentRepoImpl{b.NewBaseRepo(b.GetCollection(b.Comment)), q}
+func NewCommentRepo(db database.Database, q utils.QueryUtils) CommentRepo {
+	return &CommentRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Comment)), q}
 }
 
 func (r *CommentRepoImpl) CreateComment(ctx context.Context, data *Comment) error {
+	ctx, span := tracing.Start(ctx, "CommentRepo.CreateComment")
+	defer span.End()
+
 	result, err := r.Create(ctx, &data)
 	if err != nil {
 		return err
@@ -27,27 +33,46 @@ func (r *CommentRepoImpl) CreateComment(ctx context.Context, data *Comment) erro
 }
 
 func (r *CommentRepoImpl) FindById(ctx context.Context, id primitive.ObjectID, data *Comment) error {
-	return r.FindOneById(ctx, id, data)
+	ctx, span := tracing.Start(ctx, "CommentRepo.FindById")
+	defer span.End()
+
+	return r.FindOneByQuery(ctx, append(bson.D{{Key: "_id", Value: id}}, ActiveFilter("")...), data)
 }
 
 func (r *CommentRepoImpl) DeleteOne(ctx context.Context, id primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "CommentRepo.DeleteOne")
+	defer span.End()
+
 	return r.DeleteOneById(ctx, id)
 }
 
 func (r *CommentRepoImpl) CreateMany(ctx context.Context, datas []any) (*mongo.InsertManyResult, error) {
+	ctx, span := tracing.Start(ctx, "CommentRepo.CreateMany")
+	defer span.End()
+
 	return r.InsertMany(ctx, datas)
 }
 
 func (r *CommentRepoImpl) CreateReply(ctx context.Context, id primitive.ObjectID, data *ReplyComment) error {
+	ctx, span := tracing.Start(ctx, "CommentRepo.CreateReply")
+	defer span.End()
+
+	data.Id = primitive.NewObjectID()
 	result, err := r.UpdateOneByQuery(ctx, id, bson.M{"$push": bson.M{"reply": data}})
 	if err != nil {
 		return err
 	}
-	data.Id = result.UpsertedID.(primitive.ObjectID)
+
+	if result.MatchedCount < 1 {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
 	return nil
 }
 
 func (r *CommentRepoImpl) DeleteReplyByPostId(ctx context.Context, postId primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "CommentRepo.DeleteReplyByPostId")
+	defer span.End()
+
 	cursor, err := r.FindByQuery(ctx, bson.M{"postId": postId})
 	if err != nil {
 		return err
@@ -78,27 +103,41 @@ func (r *CommentRepoImpl) DeleteReplyByPostId(ctx context.Context, postId primit
 }
 
 func (r *CommentRepoImpl) FindReplyById(ctx context.Context, id, replyId primitive.ObjectID, data *ReplyComment) error {
-	return r.FindOneByQuery(ctx, bson.M{
-		"_id": id,
-		"reply": bson.M{
-			"$elemMatch": bson.M{
-				"_id": replyId,
-			},
-		},
-	}, &data)
+	ctx, span := tracing.Start(ctx, "CommentRepo.FindReplyById")
+	defer span.End()
+
+	cursor, err := r.Aggregations(ctx, bson.A{
+		bson.D{{Key: "$match", Value: append(bson.D{{Key: "_id", Value: id}}, ActiveFilter("")...)}},
+		r.NewRawUnwind("$reply"),
+		bson.D{{Key: "$match", Value: append(bson.D{{Key: "reply._id", Value: replyId}}, ActiveFilter("reply.")...)}},
+		bson.D{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: "$reply"}}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return cursor.Decode(data)
 }
 
 func (r *CommentRepoImpl) DeleteMany(ctx context.Context, postId primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "CommentRepo.DeleteMany")
+	defer span.End()
+
 	return r.DeleteManyByQuery(ctx, bson.M{"postId": postId})
 }
 
 func (r *CommentRepoImpl) DeleteOneReply(ctx context.Context, id, replyId primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "CommentRepo.DeleteOneReply")
+	defer span.End()
+
 	_, err := r.UpdateOneByQuery(ctx, id, bson.M{
-		"reply": bson.M{
-			"$pull": bson.M{
-				"$elemMatch": bson.M{
-					"_id": replyId,
-				},
+		"$pull": bson.M{
+			"reply": bson.M{
+				"_id": replyId,
 			},
 		},
 	})
@@ -108,9 +147,81 @@ func (r *CommentRepoImpl) DeleteOneReply(ctx context.Context, id, replyId primit
 	return nil
 }
 
-func (r *CommentRepoImpl) FindPostComment(ctx context.Context, postId primitive.ObjectID, query struct{ Page, Limit int }) ([]CommentResponse, error) {
+func (r *CommentRepoImpl) SoftDelete(ctx context.Context, id primitive.ObjectID, userId string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "CommentRepo.SoftDelete")
+	defer span.End()
+
+	result, err := r.UpdateOne(ctx, bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}}, bson.M{
+		"$set": bson.M{
+			"deletedAt": time.Now(),
+			"deletedBy": userId,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if result.MatchedCount < 1 {
+		return 0, h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *CommentRepoImpl) SoftDeleteReply(ctx context.Context, id, replyId primitive.ObjectID, userId string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "CommentRepo.SoftDeleteReply")
+	defer span.End()
+
+	result, err := r.UpdateOne(ctx, bson.M{
+		"_id": id,
+		"reply": bson.M{
+			"$elemMatch": bson.M{
+				"_id":       replyId,
+				"deletedAt": bson.M{"$exists": false},
+			},
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"reply.$.deletedAt": time.Now(),
+			"reply.$.deletedBy": userId,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if result.MatchedCount < 1 {
+		return 0, h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *CommentRepoImpl) PurgeDeleted(ctx context.Context, before time.Time) error {
+	ctx, span := tracing.Start(ctx, "CommentRepo.PurgeDeleted")
+	defer span.End()
+
+	if err := r.DeleteManyByQuery(ctx, bson.M{"deletedAt": bson.M{"$lt": before}}); err != nil {
+		return err
+	}
+
+	_, err := r.UpdateMany(ctx, bson.M{"reply.deletedAt": bson.M{"$lt": before}}, bson.M{
+		"$pull": bson.M{
+			"reply": bson.M{
+				"deletedAt": bson.M{"$lt": before},
+			},
+		},
+	})
+	return err
+}
+
+func (r *CommentRepoImpl) FindPostComment(ctx context.Context, postId primitive.ObjectID, excluded []string, query struct{ Page, Limit int }) ([]CommentResponse, error) {
+	ctx, span := tracing.Start(ctx, "CommentRepo.FindPostComment")
+	defer span.End()
+
 	curr, err := r.Aggregations(ctx, bson.A{
-		bson.D{{Key: "$match", Value: bson.D{{Key: "postId", Value: postId}}}},
+		bson.D{{Key: "$match", Value: append(bson.D{
+			{Key: "postId", Value: postId},
+			{Key: "userId", Value: bson.D{{Key: "$nin", Value: excluded}}},
+		}, ActiveFilter("")...)}},
 		bson.D{
 			{Key: "$facet",
 				Value: bson.D{
@@ -140,7 +251,15 @@ func (r *CommentRepoImpl) FindPostComment(ctx context.Context, postId primitive.
 					{Key: "userId", Value: "$data.userId"},
 					{Key: "createdAt", Value: "$data.createdAt"},
 					{Key: "updatedAt", Value: "$data.updatedAt"},
-					{Key: "reply", Value: "$data.reply"},
+					{Key: "reply", Value: bson.D{
+						{Key: "$filter", Value: bson.D{
+							{Key: "input", Value: "$data.reply"},
+							{Key: "cond", Value: bson.D{{Key: "$and", Value: bson.A{
+								ActiveReplyCond(),
+								bson.D{{Key: "$not", Value: bson.A{bson.D{{Key: "$in", Value: bson.A{"$$this.userId", excluded}}}}}},
+							}}}},
+						}},
+					}},
 					{Key: "totalData", Value: "$total.total"},
 				},
 			},
@@ -166,3 +285,121 @@ func (r *CommentRepoImpl) FindPostComment(ctx context.Context, postId primitive.
 	}
 	return datas, nil
 }
+
+func (r *CommentRepoImpl) Hide(ctx context.Context, id primitive.ObjectID, userId string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "CommentRepo.Hide")
+	defer span.End()
+
+	result, err := r.UpdateOne(ctx, append(bson.D{{Key: "_id", Value: id}}, ActiveFilter("")...), bson.M{
+		"$set": bson.M{
+			"hiddenAt": time.Now(),
+			"hiddenBy": userId,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if result.MatchedCount < 1 {
+		return 0, h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *CommentRepoImpl) HideReply(ctx context.Context, id, replyId primitive.ObjectID, userId string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "CommentRepo.HideReply")
+	defer span.End()
+
+	result, err := r.UpdateOne(ctx, bson.M{
+		"_id": id,
+		"reply": bson.M{
+			"$elemMatch": append(bson.D{{Key: "_id", Value: replyId}}, ActiveFilter("")...),
+		},
+	}, bson.M{
+		"$set": bson.M{
+			"reply.$.hiddenAt": time.Now(),
+			"reply.$.hiddenBy": userId,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if result.MatchedCount < 1 {
+		return 0, h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *CommentRepoImpl) FindHiddenById(ctx context.Context, id primitive.ObjectID, data *Comment) error {
+	ctx, span := tracing.Start(ctx, "CommentRepo.FindHiddenById")
+	defer span.End()
+
+	return r.FindOneByQuery(ctx, append(bson.D{{Key: "_id", Value: id}}, HiddenFilter("")...), data)
+}
+
+func (r *CommentRepoImpl) FindHiddenReplyById(ctx context.Context, id, replyId primitive.ObjectID, data *ReplyComment) error {
+	ctx, span := tracing.Start(ctx, "CommentRepo.FindHiddenReplyById")
+	defer span.End()
+
+	cursor, err := r.Aggregations(ctx, bson.A{
+		bson.D{{Key: "$match", Value: append(bson.D{{Key: "_id", Value: id}}, ActiveFilter("")...)}},
+		r.NewRawUnwind("$reply"),
+		bson.D{{Key: "$match", Value: append(bson.D{{Key: "reply._id", Value: replyId}}, HiddenFilter("reply.")...)}},
+		bson.D{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: "$reply"}}}},
+	})
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	if !cursor.Next(ctx) {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return cursor.Decode(data)
+}
+
+func (r *CommentRepoImpl) Unhide(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	ctx, span := tracing.Start(ctx, "CommentRepo.Unhide")
+	defer span.End()
+
+	result, err := r.UpdateOne(ctx, append(bson.D{{Key: "_id", Value: id}}, HiddenFilter("")...), bson.M{
+		"$unset": bson.M{
+			"hiddenAt": "",
+			"hiddenBy": "",
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if result.MatchedCount < 1 {
+		return 0, h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *CommentRepoImpl) UnhideReply(ctx context.Context, id, replyId primitive.ObjectID) (int64, error) {
+	ctx, span := tracing.Start(ctx, "CommentRepo.UnhideReply")
+	defer span.End()
+
+	result, err := r.UpdateOne(ctx, bson.M{
+		"_id": id,
+		"reply": bson.M{
+			"$elemMatch": append(bson.D{{Key: "_id", Value: replyId}}, HiddenFilter("")...),
+		},
+	}, bson.M{
+		"$unset": bson.M{
+			"reply.$.hiddenAt": "",
+			"reply.$.hiddenBy": "",
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if result.MatchedCount < 1 {
+		return 0, h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return result.ModifiedCount, nil
+}
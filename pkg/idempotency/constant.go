@@ -0,0 +1,25 @@
+package idempotency
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+)
+
+type IdempotencyRepo interface {
+	Reserve(ctx context.Context, data *Record) error
+	FindByKey(ctx context.Context, key string, result *Record) error
+	Complete(ctx context.Context, key string, response []byte) error
+	Release(ctx context.Context, key string) error
+}
+
+type IdempotencyRepoImpl struct{ base.BaseRepo }
+
+type Status string
+
+const (
+	Pending   Status = "Pending"
+	Completed Status = "Completed"
+)
+
+const HEADERKEY = "idempotency-key"
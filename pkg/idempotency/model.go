@@ -0,0 +1,19 @@
+package idempotency
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Record struct {
+	Id          primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Key         string             `json:"key" bson:"key"`
+	UserId      string             `json:"userId" bson:"userId"`
+	Method      string             `json:"method" bson:"method"`
+	RequestHash string             `json:"requestHash" bson:"requestHash"`
+	Status      Status             `json:"status" bson:"status"`
+	Response    []byte             `json:"response" bson:"response,omitempty"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	ExpiresAt   time.Time          `json:"expiresAt" bson:"expiresAt"`
+}
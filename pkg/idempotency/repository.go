@@ -0,0 +1,53 @@
+package idempotency
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func NewIdempotencyRepo(db database.Database) IdempotencyRepo {
+	return &IdempotencyRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Idempotency))}
+}
+
+func (r *IdempotencyRepoImpl) Reserve(ctx context.Context, data *Record) error {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepo.Reserve")
+	defer span.End()
+
+	id, err := r.Create(ctx, data)
+	if err != nil {
+		return err
+	}
+	data.Id = id
+	return nil
+}
+
+func (r *IdempotencyRepoImpl) FindByKey(ctx context.Context, key string, result *Record) error {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepo.FindByKey")
+	defer span.End()
+
+	return r.FindOneByQuery(ctx, bson.M{"key": key}, result)
+}
+
+func (r *IdempotencyRepoImpl) Complete(ctx context.Context, key string, response []byte) error {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepo.Complete")
+	defer span.End()
+
+	_, err := r.UpdateOne(ctx, bson.M{"key": key}, bson.M{
+		"$set": bson.M{
+			"status":   Completed,
+			"response": response,
+		},
+	})
+	return err
+}
+
+func (r *IdempotencyRepoImpl) Release(ctx context.Context, key string) error {
+	ctx, span := tracing.Start(ctx, "IdempotencyRepo.Release")
+	defer span.End()
+
+	return r.DeleteOneByQuery(ctx, bson.M{"key": key})
+}
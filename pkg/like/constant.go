@@ -14,9 +14,9 @@ type LikeRepo interface {
 	DeletePostLikes(ctx context.Context, postId primitive.ObjectID) error
 	GetLikesByUserIdAndPostId(ctx context.Context, postId primitive.ObjectID, userId string, result *Like) error
 	AddLikes(ctx context.Context, like *Like) (primitive.ObjectID, error)
-	DeleteLike(ctx context.Context, postId primitive.ObjectID, userId string) error
+	DeleteLike(ctx context.Context, postId primitive.ObjectID, userId string) (int64, error)
 	CreateMany(ctx context.Context, datas []any) (*mongo.InsertManyResult, error)
-	GetSession() (mongo.Session, error)
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 	FindUserLikedPost(ctx context.Context, userId string, in base.Pagination) ([]post.PostResponse, error)
 	CountPostLikes(ctx context.Context, ids []primitive.ObjectID) ([]PostLikes, error)
 }
@@ -3,9 +3,11 @@ package like
 import (
 	"context"
 
+	"github.com/forum-gamers/nine-tails-fox/database"
 	h "github.com/forum-gamers/nine-tails-fox/helpers"
 	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
 	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
 	"github.com/forum-gamers/nine-tails-fox/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -13,38 +15,76 @@ import (
 	"google.golang.org/grpc/codes"
 )
 
-func NewLikeRepo(q utils.QueryUtils) LikeRepo {
-	return &LikeRepoImpl{b.NewBaseRepo(b.GetCollection(b.Like)), q}
+func NewLikeRepo(db database.Database, q utils.QueryUtils) LikeRepo {
+	return &LikeRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Like)), q}
 }
 
 func (r *LikeRepoImpl) DeletePostLikes(ctx context.Context, postId primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "LikeRepo.DeletePostLikes")
+	defer span.End()
+
 	return r.DeleteManyByQuery(ctx, bson.M{"postId": postId})
 }
 
 func (r *LikeRepoImpl) GetLikesByUserIdAndPostId(ctx context.Context, postId primitive.ObjectID, userId string, result *Like) error {
+	ctx, span := tracing.Start(ctx, "LikeRepo.GetLikesByUserIdAndPostId")
+	defer span.End()
+
 	return r.FindOneByQuery(ctx, bson.M{"userId": userId, "postId": postId}, &result)
 }
 
 func (r *LikeRepoImpl) AddLikes(ctx context.Context, like *Like) (primitive.ObjectID, error) {
-	return r.Create(ctx, like)
+	ctx, span := tracing.Start(ctx, "LikeRepo.AddLikes")
+	defer span.End()
+
+	id, created, err := r.InsertIfNotExists(ctx, bson.M{"userId": like.UserId, "postId": like.PostId}, like)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	if !created {
+		return primitive.NilObjectID, h.NewAppError(codes.AlreadyExists, "Conflict")
+	}
+	return id, nil
 }
 
-func (r *LikeRepoImpl) DeleteLike(ctx context.Context, postId primitive.ObjectID, userId string) error {
-	return r.DeleteOneByQuery(ctx, bson.M{"postId": postId, "userId": userId})
+// DeleteLike removes the user's like and returns how many were deleted, 0
+// when a concurrent request removed it first.
+func (r *LikeRepoImpl) DeleteLike(ctx context.Context, postId primitive.ObjectID, userId string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "LikeRepo.DeleteLike")
+	defer span.End()
+
+	result, err := r.DeleteOne(ctx, bson.M{"postId": postId, "userId": userId})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
 }
 
 func (r *LikeRepoImpl) CreateMany(ctx context.Context, datas []any) (*mongo.InsertManyResult, error) {
+	ctx, span := tracing.Start(ctx, "LikeRepo.CreateMany")
+	defer span.End()
+
 	return r.InsertMany(ctx, datas)
 }
 
-func (r *LikeRepoImpl) GetSession() (mongo.Session, error) {
-	return r.BaseRepo.GetSession()
+func (r *LikeRepoImpl) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, span := tracing.Start(ctx, "LikeRepo.WithTransaction")
+	defer span.End()
+
+	return r.BaseRepo.WithTransaction(ctx, fn)
 }
 
 func (r *LikeRepoImpl) FindUserLikedPost(ctx context.Context, userId string, query b.Pagination) ([]post.PostResponse, error) {
+	ctx, span := tracing.Start(ctx, "LikeRepo.FindUserLikedPost")
+	defer span.End()
+
 	curr, err := r.Aggregations(ctx, bson.A{
 		bson.D{{Key: "$match", Value: bson.D{{Key: "userId", Value: userId}}}},
 		bson.D{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}}}},
+		r.NewLookup("post", "postId", "_id", "post"),
+		r.NewRawUnwind("$post"),
+		bson.D{{Key: "$match", Value: post.VisibleFilter("post.")}},
 		bson.D{
 			{Key: "$facet",
 				Value: bson.D{
@@ -57,16 +97,11 @@ func (r *LikeRepoImpl) FindUserLikedPost(ctx context.Context, userId string, que
 						Value: bson.A{
 							r.NewSkip(int((query.Page - 1) * query.Limit)),
 							r.NewLimit(int(query.Limit)),
-							r.NewLookup("post", "postId", "_id", "post"),
-							r.NewRawUnwind("$post"),
-							r.NewLookup("comment", "post._id", "postId", "comment"),
-							r.NewLookup("share", "post._id", "postId", "share"),
+							r.NewUserLookup("share", "post._id", "postId", userId, "share"),
 							bson.D{
 								{Key: "$addFields",
 									Value: bson.D{
-										{Key: "countShare", Value: bson.D{{Key: "$size", Value: "$share"}}},
-										r.NewCountComment("countComment", "$comment"),
-										r.IsDo("isShared", "$share", userId),
+										r.IsExists("isShared", "$share"),
 									},
 								},
 							},
@@ -81,8 +116,6 @@ func (r *LikeRepoImpl) FindUserLikedPost(ctx context.Context, userId string, que
 			{Key: "$project",
 				Value: bson.D{
 					{Key: "post", Value: "$datas.post"},
-					{Key: "countComment", Value: "$datas.countComment"},
-					{Key: "countShare", Value: "$datas.countShare"},
 					{Key: "isShared", Value: "$datas.isShared"},
 					{Key: "total", Value: "$total.total"},
 				},
@@ -95,15 +128,18 @@ func (r *LikeRepoImpl) FindUserLikedPost(ctx context.Context, userId string, que
 					{Key: "userId", Value: "$post.userId"},
 					{Key: "text", Value: "$post.text"},
 					{Key: "media", Value: "$post.media"},
+					{Key: "poll", Value: "$post.poll"},
+					{Key: "previews", Value: "$post.previews"},
 					{Key: "allowComment", Value: "$post.allowComment"},
 					{Key: "createdAt", Value: "$post.createdAt"},
 					{Key: "updatedAt", Value: "$post.updatedAt"},
-					{Key: "countComment", Value: 1},
+					{Key: "countLike", Value: "$post.countLike"},
+					{Key: "countComment", Value: "$post.countComment"},
+					{Key: "countShare", Value: "$post.countShare"},
 					{Key: "isShared", Value: 1},
 					{Key: "tags", Value: "$post.tags"},
 					{Key: "privacy", Value: "$post.privacy"},
 					{Key: "totalData", Value: "$total"},
-					{Key: "countShare", Value: 1},
 				},
 			},
 		},
@@ -133,6 +169,9 @@ func (r *LikeRepoImpl) FindUserLikedPost(ctx context.Context, userId string, que
 }
 
 func (r *LikeRepoImpl) CountPostLikes(ctx context.Context, ids []primitive.ObjectID) ([]PostLikes, error) {
+	ctx, span := tracing.Start(ctx, "LikeRepo.CountPostLikes")
+	defer span.End()
+
 	curr, err := r.Aggregations(ctx, bson.A{
 		bson.D{
 			{Key: "$match",
@@ -0,0 +1,17 @@
+package lock
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+)
+
+const Pin = "pin"
+
+type LockRepo interface {
+	Acquire(ctx context.Context, name, userId string) error
+}
+
+type LockRepoImpl struct {
+	base.BaseRepo
+}
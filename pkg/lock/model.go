@@ -0,0 +1,17 @@
+package lock
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Lock is written by every transaction that must not run concurrently with
+// another one for the same Name and UserId.
+type Lock struct {
+	Id         primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Name       string             `json:"name" bson:"name"`
+	UserId     string             `json:"userId" bson:"userId"`
+	Version    int64              `json:"version" bson:"version"`
+	AcquiredAt time.Time          `json:"acquiredAt" bson:"acquiredAt"`
+}
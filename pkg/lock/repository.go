@@ -0,0 +1,29 @@
+package lock
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func NewLockRepo(db database.Database) LockRepo {
+	return &LockRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Lock))}
+}
+
+// Acquire bumps the lock document of the user. Called inside a transaction it
+// makes concurrent transactions taking the same lock conflict, the driver then
+// retries the loser once the winner committed so it sees its writes.
+func (r *LockRepoImpl) Acquire(ctx context.Context, name, userId string) error {
+	ctx, span := tracing.Start(ctx, "LockRepo.Acquire")
+	defer span.End()
+
+	_, err := r.UpsertOne(ctx, bson.M{"name": name, "userId": userId}, bson.M{
+		"$inc": bson.M{"version": 1},
+		"$set": bson.M{"acquiredAt": time.Now()},
+	})
+	return err
+}
@@ -0,0 +1,24 @@
+package media
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+)
+
+type CleanupRepo interface {
+	Create(ctx context.Context, data *Cleanup) error
+}
+
+type CleanupRepoImpl struct {
+	base.BaseRepo
+}
+
+type CleanupService interface {
+	Enqueue(ctx context.Context, data post.Post) error
+}
+
+type CleanupServiceImpl struct {
+	Repo CleanupRepo
+}
@@ -0,0 +1,17 @@
+package media
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Cleanup asks the upload service to delete the files of a purged post. The
+// upload service consumes the collection and removes entries it handled.
+type Cleanup struct {
+	Id        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	PostId    primitive.ObjectID `json:"postId" bson:"postId"`
+	UserId    string             `json:"userId" bson:"userId"`
+	MediaIds  []string           `json:"mediaIds" bson:"mediaIds"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
@@ -0,0 +1,25 @@
+package media
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
+)
+
+func NewCleanupRepo(db database.Database) CleanupRepo {
+	return &CleanupRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.MediaCleanup))}
+}
+
+func (r *CleanupRepoImpl) Create(ctx context.Context, data *Cleanup) error {
+	ctx, span := tracing.Start(ctx, "CleanupRepo.Create")
+	defer span.End()
+
+	id, err := r.BaseRepo.Create(ctx, data)
+	if err != nil {
+		return err
+	}
+	data.Id = id
+	return nil
+}
@@ -0,0 +1,32 @@
+package media
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+)
+
+func NewCleanupService(r CleanupRepo) CleanupService {
+	return &CleanupServiceImpl{r}
+}
+
+// Enqueue queues the files of a purged post for deletion. Posts without media
+// are skipped.
+func (s *CleanupServiceImpl) Enqueue(ctx context.Context, data post.Post) error {
+	if len(data.Media) < 1 {
+		return nil
+	}
+
+	mediaIds := make([]string, 0, len(data.Media))
+	for _, media := range data.Media {
+		mediaIds = append(mediaIds, media.Id)
+	}
+
+	return s.Repo.Create(ctx, &Cleanup{
+		PostId:    data.Id,
+		UserId:    data.UserId,
+		MediaIds:  mediaIds,
+		CreatedAt: time.Now(),
+	})
+}
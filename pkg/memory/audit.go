@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewAuditRepo(s *Store) audit.AuditRepo {
+	return &AuditRepoImpl{s}
+}
+
+func (r *AuditRepoImpl) Create(ctx context.Context, data *audit.Entry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data.Id = primitive.NewObjectID()
+	r.AuditLog = append(r.AuditLog, *data)
+	return nil
+}
+
+func (r *AuditRepoImpl) Query(ctx context.Context, query audit.Query) ([]audit.Entry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var datas []audit.Entry
+	for _, data := range r.AuditLog {
+		if (query.ActorId != "" && data.ActorId != query.ActorId) ||
+			(query.Action != "" && data.Action != query.Action) ||
+			(query.TargetType != "" && data.TargetType != query.TargetType) ||
+			(!query.TargetId.IsZero() && data.TargetId != query.TargetId) ||
+			(query.From != nil && data.CreatedAt.Before(*query.From)) ||
+			(query.To != nil && !data.CreatedAt.Before(*query.To)) {
+			continue
+		}
+		datas = append(datas, data)
+	}
+
+	sort.SliceStable(datas, func(i, j int) bool {
+		return datas[i].CreatedAt.After(datas[j].CreatedAt)
+	})
+
+	result := paginate(datas, query.Page, query.Limit)
+	if len(result) < 1 {
+		return result, errEmptyResult()
+	}
+
+	for i := range result {
+		result[i].TotalData = len(datas)
+	}
+	return result, nil
+}
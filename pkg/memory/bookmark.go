@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/pkg/bookmark"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewBookmarkRepo(s *Store) bookmark.BookmarkRepo {
+	return &BookmarkRepoImpl{s}
+}
+
+func (r *BookmarkRepoImpl) CreateOne(ctx context.Context, data *bookmark.Bookmark) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, bookmark := range r.Bookmarks {
+		if bookmark.PostId == data.PostId && bookmark.UserId == data.UserId {
+			return errConflict()
+		}
+	}
+
+	data.Id = primitive.NewObjectID()
+	r.Bookmarks = append(r.Bookmarks, *data)
+	return nil
+}
+
+func (r *BookmarkRepoImpl) FindOne(ctx context.Context, query any, result *bookmark.Bookmark) error {
+	filter, err := toFilter(query)
+	if err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, data := range r.Bookmarks {
+		if matchBookmark(data, filter) {
+			*result = data
+			return nil
+		}
+	}
+	return errNotFound()
+}
+
+func (r *BookmarkRepoImpl) FindById(ctx context.Context, id primitive.ObjectID, result *bookmark.Bookmark) error {
+	return r.FindOne(ctx, bson.M{"_id": id}, result)
+}
+
+func (r *BookmarkRepoImpl) DeleteOneById(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, data := range r.Bookmarks {
+		if data.Id == id {
+			r.Bookmarks = append(r.Bookmarks[:i], r.Bookmarks[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *BookmarkRepoImpl) FindByPostIdAndUserId(ctx context.Context, postId primitive.ObjectID, userId string, result *bookmark.Bookmark) error {
+	return r.FindOne(ctx, bson.M{"postId": postId, "userId": userId}, result)
+}
+
+func (r *BookmarkRepoImpl) FindMyBookmarks(ctx context.Context, postId primitive.ObjectID, userId string, query base.Pagination) ([]post.PostResponse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	bookmarks := make([]bookmark.Bookmark, 0)
+	for _, data := range r.Bookmarks {
+		if _, ok := r.findActivePost(data.PostId); ok && data.UserId == userId {
+			bookmarks = append(bookmarks, data)
+		}
+	}
+	sort.SliceStable(bookmarks, func(i, j int) bool { return bookmarks[i].CreatedAt.Before(bookmarks[j].CreatedAt) })
+
+	var datas []post.PostResponse
+	for _, data := range paginate(bookmarks, int(query.Page), int(query.Limit)) {
+		if i, ok := r.findPost(data.PostId); ok {
+			datas = append(datas, r.postResponse(r.Posts[i], userId))
+		}
+	}
+
+	if len(datas) < 1 {
+		return datas, errEmptyResult()
+	}
+	return withTotal(datas, len(bookmarks)), nil
+}
+
+func toFilter(query any) (bson.M, error) {
+	switch q := query.(type) {
+	case bson.M:
+		return q, nil
+	case bson.D:
+		filter := bson.M{}
+		for _, e := range q {
+			filter[e.Key] = e.Value
+		}
+		return filter, nil
+	default:
+		return nil, fmt.Errorf("unsupported query type %T", query)
+	}
+}
+
+func matchBookmark(data bookmark.Bookmark, filter bson.M) bool {
+	for key, val := range filter {
+		switch key {
+		case "_id":
+			if data.Id != val {
+				return false
+			}
+		case "postId":
+			if data.PostId != val {
+				return false
+			}
+		case "userId":
+			if data.UserId != val {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,356 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func NewCommentRepo(s *Store) comment.CommentRepo {
+	return &CommentRepoImpl{s}
+}
+
+func (r *CommentRepoImpl) findComment(id primitive.ObjectID) (int, bool) {
+	for i, data := range r.Comments {
+		if data.Id == id {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (r *CommentRepoImpl) CreateComment(ctx context.Context, data *comment.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data.Id = primitive.NewObjectID()
+	if data.Reply == nil {
+		data.Reply = []comment.ReplyComment{}
+	}
+	r.Comments = append(r.Comments, *data)
+	return nil
+}
+
+func (r *CommentRepoImpl) CreateReply(ctx context.Context, id primitive.ObjectID, data *comment.ReplyComment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findComment(id)
+	if !ok {
+		return errNotFound()
+	}
+
+	data.Id = primitive.NewObjectID()
+	r.Comments[i].Reply = append(r.Comments[i].Reply, *data)
+	return nil
+}
+
+func (r *CommentRepoImpl) FindById(ctx context.Context, id primitive.ObjectID, data *comment.Comment) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.findComment(id)
+	if !ok || !isActiveComment(r.Comments[i]) {
+		return errNotFound()
+	}
+	*data = r.Comments[i]
+	return nil
+}
+
+func (r *CommentRepoImpl) DeleteOne(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i, ok := r.findComment(id); ok {
+		r.Comments = append(r.Comments[:i], r.Comments[i+1:]...)
+	}
+	return nil
+}
+
+func (r *CommentRepoImpl) CreateMany(ctx context.Context, datas []any) (*mongo.InsertManyResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := &mongo.InsertManyResult{}
+	for _, data := range datas {
+		var value comment.Comment
+		switch v := data.(type) {
+		case comment.Comment:
+			value = v
+		case *comment.Comment:
+			value = *v
+		default:
+			return nil, fmt.Errorf("unsupported document type %T", data)
+		}
+
+		value.Id = primitive.NewObjectID()
+		r.Comments = append(r.Comments, value)
+		result.InsertedIDs = append(result.InsertedIDs, value.Id)
+	}
+	return result, nil
+}
+
+func (r *CommentRepoImpl) DeleteReplyByPostId(ctx context.Context, postId primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, data := range r.Comments {
+		if data.PostId == postId {
+			r.Comments[i].Reply = []comment.ReplyComment{}
+		}
+	}
+	return nil
+}
+
+func (r *CommentRepoImpl) FindReplyById(ctx context.Context, id, replyId primitive.ObjectID, data *comment.ReplyComment) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.findComment(id)
+	if !ok || !isActiveComment(r.Comments[i]) {
+		return errNotFound()
+	}
+
+	for _, reply := range r.Comments[i].Reply {
+		if reply.Id == replyId && isActiveReply(reply) {
+			*data = reply
+			return nil
+		}
+	}
+	return errNotFound()
+}
+
+func (r *CommentRepoImpl) DeleteOneReply(ctx context.Context, id, replyId primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findComment(id)
+	if !ok {
+		return nil
+	}
+
+	replies := r.Comments[i].Reply
+	for j, reply := range replies {
+		if reply.Id == replyId {
+			r.Comments[i].Reply = append(replies[:j], replies[j+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *CommentRepoImpl) DeleteMany(ctx context.Context, postId primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comments := r.Comments[:0]
+	for _, data := range r.Comments {
+		if data.PostId != postId {
+			comments = append(comments, data)
+		}
+	}
+	r.Comments = comments
+	return nil
+}
+
+func (r *CommentRepoImpl) SoftDelete(ctx context.Context, id primitive.ObjectID, userId string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findComment(id)
+	if !ok || r.Comments[i].DeletedAt != nil {
+		return 0, errNotFound()
+	}
+
+	now := time.Now()
+	r.Comments[i].DeletedAt = &now
+	r.Comments[i].DeletedBy = userId
+	return 1, nil
+}
+
+func (r *CommentRepoImpl) SoftDeleteReply(ctx context.Context, id, replyId primitive.ObjectID, userId string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findComment(id)
+	if !ok {
+		return 0, errNotFound()
+	}
+
+	for j, reply := range r.Comments[i].Reply {
+		if reply.Id == replyId && reply.DeletedAt == nil {
+			now := time.Now()
+			r.Comments[i].Reply[j].DeletedAt = &now
+			r.Comments[i].Reply[j].DeletedBy = userId
+			return 1, nil
+		}
+	}
+	return 0, errNotFound()
+}
+
+func (r *CommentRepoImpl) PurgeDeleted(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comments := r.Comments[:0]
+	for _, data := range r.Comments {
+		if data.DeletedAt != nil && data.DeletedAt.Before(before) {
+			continue
+		}
+
+		replies := make([]comment.ReplyComment, 0, len(data.Reply))
+		for _, reply := range data.Reply {
+			if reply.DeletedAt == nil || !reply.DeletedAt.Before(before) {
+				replies = append(replies, reply)
+			}
+		}
+		data.Reply = replies
+		comments = append(comments, data)
+	}
+	r.Comments = comments
+	return nil
+}
+
+func (r *CommentRepoImpl) FindPostComment(ctx context.Context, postId primitive.ObjectID, excluded []string, query struct{ Page, Limit int }) ([]comment.CommentResponse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var datas []comment.CommentResponse
+	for _, data := range r.Comments {
+		if data.PostId != postId || !isActiveComment(data) || slices.Contains(excluded, data.UserId) {
+			continue
+		}
+
+		replies := []comment.ReplyComment{}
+		for _, reply := range activeReplies(data.Reply) {
+			if !slices.Contains(excluded, reply.UserId) {
+				replies = append(replies, reply)
+			}
+		}
+
+		datas = append(datas, comment.CommentResponse{
+			Id:        data.Id,
+			UserId:    data.UserId,
+			Text:      data.Text,
+			PostId:    data.PostId,
+			CreatedAt: data.CreatedAt,
+			UpdatedAt: data.UpdatedAt,
+			Reply:     replies,
+		})
+	}
+	sort.SliceStable(datas, func(i, j int) bool { return datas[i].CreatedAt.After(datas[j].CreatedAt) })
+
+	result := paginate(datas, query.Page, query.Limit)
+	if len(result) < 1 {
+		return result, errEmptyResult()
+	}
+
+	for i := range result {
+		result[i].TotalData = len(datas)
+	}
+	return result, nil
+}
+
+func (r *CommentRepoImpl) Hide(ctx context.Context, id primitive.ObjectID, userId string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findComment(id)
+	if !ok || !isActiveComment(r.Comments[i]) {
+		return 0, errNotFound()
+	}
+
+	now := time.Now()
+	r.Comments[i].HiddenAt = &now
+	r.Comments[i].HiddenBy = userId
+	return 1, nil
+}
+
+func (r *CommentRepoImpl) HideReply(ctx context.Context, id, replyId primitive.ObjectID, userId string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findComment(id)
+	if !ok {
+		return 0, errNotFound()
+	}
+
+	for j, reply := range r.Comments[i].Reply {
+		if reply.Id == replyId && isActiveReply(reply) {
+			now := time.Now()
+			r.Comments[i].Reply[j].HiddenAt = &now
+			r.Comments[i].Reply[j].HiddenBy = userId
+			return 1, nil
+		}
+	}
+	return 0, errNotFound()
+}
+
+func (r *CommentRepoImpl) FindHiddenById(ctx context.Context, id primitive.ObjectID, data *comment.Comment) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.findComment(id)
+	if !ok || r.Comments[i].DeletedAt != nil || r.Comments[i].HiddenAt == nil {
+		return errNotFound()
+	}
+	*data = r.Comments[i]
+	return nil
+}
+
+func (r *CommentRepoImpl) FindHiddenReplyById(ctx context.Context, id, replyId primitive.ObjectID, data *comment.ReplyComment) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.findComment(id)
+	if !ok || !isActiveComment(r.Comments[i]) {
+		return errNotFound()
+	}
+
+	for _, reply := range r.Comments[i].Reply {
+		if reply.Id == replyId && reply.DeletedAt == nil && reply.HiddenAt != nil {
+			*data = reply
+			return nil
+		}
+	}
+	return errNotFound()
+}
+
+func (r *CommentRepoImpl) Unhide(ctx context.Context, id primitive.ObjectID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findComment(id)
+	if !ok || r.Comments[i].DeletedAt != nil || r.Comments[i].HiddenAt == nil {
+		return 0, errNotFound()
+	}
+
+	r.Comments[i].HiddenAt = nil
+	r.Comments[i].HiddenBy = ""
+	return 1, nil
+}
+
+func (r *CommentRepoImpl) UnhideReply(ctx context.Context, id, replyId primitive.ObjectID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findComment(id)
+	if !ok {
+		return 0, errNotFound()
+	}
+
+	for j, reply := range r.Comments[i].Reply {
+		if reply.Id == replyId && reply.DeletedAt == nil && reply.HiddenAt != nil {
+			r.Comments[i].Reply[j].HiddenAt = nil
+			r.Comments[i].Reply[j].HiddenBy = ""
+			return 1, nil
+		}
+	}
+	return 0, errNotFound()
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
+	"github.com/forum-gamers/nine-tails-fox/pkg/bookmark"
+	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
+	"github.com/forum-gamers/nine-tails-fox/pkg/idempotency"
+	"github.com/forum-gamers/nine-tails-fox/pkg/like"
+	"github.com/forum-gamers/nine-tails-fox/pkg/lock"
+	"github.com/forum-gamers/nine-tails-fox/pkg/media"
+	"github.com/forum-gamers/nine-tails-fox/pkg/moderation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/poll"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/preference"
+	"github.com/forum-gamers/nine-tails-fox/pkg/preview"
+	"github.com/forum-gamers/nine-tails-fox/pkg/relation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/share"
+	"github.com/forum-gamers/nine-tails-fox/pkg/spam"
+)
+
+type Store struct {
+	mu           sync.RWMutex
+	Posts        []post.Post
+	Likes        []like.Like
+	Comments     []comment.Comment
+	Shares       []share.Share
+	Bookmarks    []bookmark.Bookmark
+	Preferences  []preference.UserPreference
+	Idempotency  []idempotency.Record
+	Votes        []poll.Vote
+	Reports      []moderation.Report
+	Actions      []moderation.Action
+	Warnings     []moderation.Warning
+	AuditLog     []audit.Entry
+	Fingerprints []spam.Fingerprint
+	Relations    []relation.Relation
+	Previews     []preview.Preview
+	Cleanups     []media.Cleanup
+	Locks        []lock.Lock
+}
+
+type PostRepoImpl struct{ *Store }
+
+type LikeRepoImpl struct{ *Store }
+
+type CommentRepoImpl struct{ *Store }
+
+type ShareRepoImpl struct{ *Store }
+
+type BookmarkRepoImpl struct{ *Store }
+
+type PreferenceRepoImpl struct{ *Store }
+
+type IdempotencyRepoImpl struct{ *Store }
+
+type PollRepoImpl struct{ *Store }
+
+type ReportRepoImpl struct{ *Store }
+
+type ActionRepoImpl struct{ *Store }
+
+type WarningRepoImpl struct{ *Store }
+
+type AuditRepoImpl struct{ *Store }
+
+type FingerprintRepoImpl struct{ *Store }
+
+type RelationRepoImpl struct{ *Store }
+
+type PreviewRepoImpl struct{ *Store }
+
+type CleanupRepoImpl struct{ *Store }
+
+type LockRepoImpl struct{ *Store }
+
+var (
+	_ post.PostRepo               = (*PostRepoImpl)(nil)
+	_ like.LikeRepo               = (*LikeRepoImpl)(nil)
+	_ comment.CommentRepo         = (*CommentRepoImpl)(nil)
+	_ share.ShareRepo             = (*ShareRepoImpl)(nil)
+	_ bookmark.BookmarkRepo       = (*BookmarkRepoImpl)(nil)
+	_ preference.PreferenceRepo   = (*PreferenceRepoImpl)(nil)
+	_ idempotency.IdempotencyRepo = (*IdempotencyRepoImpl)(nil)
+	_ poll.PollRepo               = (*PollRepoImpl)(nil)
+	_ moderation.ReportRepo       = (*ReportRepoImpl)(nil)
+	_ moderation.ActionRepo       = (*ActionRepoImpl)(nil)
+	_ moderation.WarningRepo      = (*WarningRepoImpl)(nil)
+	_ audit.AuditRepo             = (*AuditRepoImpl)(nil)
+	_ spam.FingerprintRepo        = (*FingerprintRepoImpl)(nil)
+	_ relation.RelationRepo       = (*RelationRepoImpl)(nil)
+	_ preview.PreviewRepo         = (*PreviewRepoImpl)(nil)
+	_ media.CleanupRepo           = (*CleanupRepoImpl)(nil)
+	_ lock.LockRepo               = (*LockRepoImpl)(nil)
+)
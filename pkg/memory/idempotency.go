@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/idempotency"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewIdempotencyRepo(s *Store) idempotency.IdempotencyRepo {
+	return &IdempotencyRepoImpl{s}
+}
+
+func (r *IdempotencyRepoImpl) findRecord(key string) (int, bool) {
+	for i, data := range r.Idempotency {
+		if data.Key == key {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (r *IdempotencyRepoImpl) Reserve(ctx context.Context, data *idempotency.Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.findRecord(data.Key); ok {
+		return errConflict()
+	}
+
+	data.Id = primitive.NewObjectID()
+	r.Idempotency = append(r.Idempotency, *data)
+	return nil
+}
+
+func (r *IdempotencyRepoImpl) FindByKey(ctx context.Context, key string, result *idempotency.Record) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.findRecord(key)
+	if !ok {
+		return errNotFound()
+	}
+	*result = r.Idempotency[i]
+	return nil
+}
+
+func (r *IdempotencyRepoImpl) Complete(ctx context.Context, key string, response []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findRecord(key)
+	if !ok {
+		return errNotFound()
+	}
+	r.Idempotency[i].Status = idempotency.Completed
+	r.Idempotency[i].Response = response
+	return nil
+}
+
+func (r *IdempotencyRepoImpl) Release(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findRecord(key)
+	if !ok {
+		return errNotFound()
+	}
+	r.Idempotency = append(r.Idempotency[:i], r.Idempotency[i+1:]...)
+	return nil
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/pkg/like"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func NewLikeRepo(s *Store) like.LikeRepo {
+	return &LikeRepoImpl{s}
+}
+
+func (r *LikeRepoImpl) DeletePostLikes(ctx context.Context, postId primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	likes := r.Likes[:0]
+	for _, data := range r.Likes {
+		if data.PostId != postId {
+			likes = append(likes, data)
+		}
+	}
+	r.Likes = likes
+	return nil
+}
+
+func (r *LikeRepoImpl) GetLikesByUserIdAndPostId(ctx context.Context, postId primitive.ObjectID, userId string, result *like.Like) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, data := range r.Likes {
+		if data.PostId == postId && data.UserId == userId {
+			*result = data
+			return nil
+		}
+	}
+	return errNotFound()
+}
+
+func (r *LikeRepoImpl) AddLikes(ctx context.Context, data *like.Like) (primitive.ObjectID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, like := range r.Likes {
+		if like.PostId == data.PostId && like.UserId == data.UserId {
+			return primitive.NilObjectID, errConflict()
+		}
+	}
+
+	data.Id = primitive.NewObjectID()
+	r.Likes = append(r.Likes, *data)
+	return data.Id, nil
+}
+
+func (r *LikeRepoImpl) DeleteLike(ctx context.Context, postId primitive.ObjectID, userId string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, data := range r.Likes {
+		if data.PostId == postId && data.UserId == userId {
+			r.Likes = append(r.Likes[:i], r.Likes[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (r *LikeRepoImpl) CreateMany(ctx context.Context, datas []any) (*mongo.InsertManyResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := &mongo.InsertManyResult{}
+	for _, data := range datas {
+		var value like.Like
+		switch v := data.(type) {
+		case like.Like:
+			value = v
+		case *like.Like:
+			value = *v
+		default:
+			return nil, fmt.Errorf("unsupported document type %T", data)
+		}
+
+		value.Id = primitive.NewObjectID()
+		r.Likes = append(r.Likes, value)
+		result.InsertedIDs = append(result.InsertedIDs, value.Id)
+	}
+	return result, nil
+}
+
+func (r *LikeRepoImpl) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *LikeRepoImpl) FindUserLikedPost(ctx context.Context, userId string, query base.Pagination) ([]post.PostResponse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	likes := make([]like.Like, 0)
+	for _, data := range r.Likes {
+		if _, ok := r.findActivePost(data.PostId); ok && data.UserId == userId {
+			likes = append(likes, data)
+		}
+	}
+	sort.SliceStable(likes, func(i, j int) bool { return likes[i].CreatedAt.After(likes[j].CreatedAt) })
+
+	var datas []post.PostResponse
+	for _, data := range paginate(likes, int(query.Page), int(query.Limit)) {
+		if i, ok := r.findPost(data.PostId); ok {
+			datas = append(datas, r.postResponse(r.Posts[i], userId))
+		}
+	}
+
+	if len(datas) < 1 {
+		return datas, errEmptyResult()
+	}
+	return withTotal(datas, len(likes)), nil
+}
+
+func (r *LikeRepoImpl) CountPostLikes(ctx context.Context, ids []primitive.ObjectID) ([]like.PostLikes, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	index := map[primitive.ObjectID]int{}
+	var datas []like.PostLikes
+	for _, data := range r.Likes {
+		for _, id := range ids {
+			if data.PostId != id {
+				continue
+			}
+
+			i, ok := index[id]
+			if !ok {
+				i = len(datas)
+				index[id] = i
+				datas = append(datas, like.PostLikes{Id: id})
+			}
+			datas[i].TotalLike++
+		}
+	}
+
+	if len(datas) < 1 {
+		return datas, errEmptyResult()
+	}
+	return datas, nil
+}
@@ -0,0 +1,36 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/lock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewLockRepo(s *Store) lock.LockRepo {
+	return &LockRepoImpl{s}
+}
+
+func (r *LockRepoImpl) Acquire(ctx context.Context, name, userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for i, data := range r.Locks {
+		if data.Name == name && data.UserId == userId {
+			r.Locks[i].Version++
+			r.Locks[i].AcquiredAt = now
+			return nil
+		}
+	}
+
+	r.Locks = append(r.Locks, lock.Lock{
+		Id:         primitive.NewObjectID(),
+		Name:       name,
+		UserId:     userId,
+		Version:    1,
+		AcquiredAt: now,
+	})
+	return nil
+}
@@ -0,0 +1,21 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/media"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewCleanupRepo(s *Store) media.CleanupRepo {
+	return &CleanupRepoImpl{s}
+}
+
+func (r *CleanupRepoImpl) Create(ctx context.Context, data *media.Cleanup) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data.Id = primitive.NewObjectID()
+	r.Cleanups = append(r.Cleanups, *data)
+	return nil
+}
@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	"github.com/forum-gamers/nine-tails-fox/pkg/moderation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+)
+
+func NewReportRepo(s *Store) moderation.ReportRepo {
+	return &ReportRepoImpl{s}
+}
+
+func NewActionRepo(s *Store) moderation.ActionRepo {
+	return &ActionRepoImpl{s}
+}
+
+func NewWarningRepo(s *Store) moderation.WarningRepo {
+	return &WarningRepoImpl{s}
+}
+
+func (r *ReportRepoImpl) Create(ctx context.Context, data *moderation.Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, report := range r.Reports {
+		if report.TargetType == data.TargetType && report.TargetId == data.TargetId && report.ReporterId == data.ReporterId && report.Status == moderation.Open {
+			return h.NewAppError(codes.AlreadyExists, "Already reported")
+		}
+	}
+
+	data.Id = primitive.NewObjectID()
+	r.Reports = append(r.Reports, *data)
+	return nil
+}
+
+func (r *ReportRepoImpl) GetQueue(ctx context.Context, query moderation.QueueQuery) ([]moderation.QueueItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	index := map[moderation.Target]int{}
+	var datas []moderation.QueueItem
+	for _, report := range r.Reports {
+		if report.Status != query.Status ||
+			(query.TargetType != "" && report.TargetType != query.TargetType) ||
+			(query.Reason != "" && report.Reason != query.Reason) {
+			continue
+		}
+
+		key := moderation.Target{TargetType: report.TargetType, TargetId: report.TargetId}
+		i, ok := index[key]
+		if !ok {
+			i = len(datas)
+			index[key] = i
+			datas = append(datas, moderation.QueueItem{
+				Target:          report.Target,
+				Reasons:         []string{},
+				Texts:           []string{},
+				FirstReportedAt: report.CreatedAt,
+			})
+		}
+
+		item := &datas[i]
+		item.ReportCount++
+		if !slices.Contains(item.Reasons, report.Reason) {
+			item.Reasons = append(item.Reasons, report.Reason)
+		}
+
+		if report.Text != "" {
+			item.Texts = append(item.Texts, report.Text)
+		}
+
+		if report.CreatedAt.Before(item.FirstReportedAt) {
+			item.FirstReportedAt = report.CreatedAt
+		}
+
+		if report.CreatedAt.After(item.LastReportedAt) {
+			item.LastReportedAt = report.CreatedAt
+		}
+	}
+
+	sort.SliceStable(datas, func(i, j int) bool {
+		if datas[i].ReportCount != datas[j].ReportCount {
+			return datas[i].ReportCount > datas[j].ReportCount
+		}
+		return datas[i].LastReportedAt.After(datas[j].LastReportedAt)
+	})
+
+	result := paginate(datas, query.Page, query.Limit)
+	if len(result) < 1 {
+		return result, errEmptyResult()
+	}
+
+	for i := range result {
+		result[i].TotalData = len(datas)
+	}
+	return result, nil
+}
+
+func (r *ReportRepoImpl) Resolve(ctx context.Context, targetType string, targetId primitive.ObjectID, status, moderatorId string, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var modified int64
+	for i, report := range r.Reports {
+		if report.TargetType != targetType || report.TargetId != targetId || report.Status != moderation.Open {
+			continue
+		}
+
+		r.Reports[i].Status = status
+		r.Reports[i].ResolvedAt = &at
+		r.Reports[i].ResolvedBy = moderatorId
+		modified++
+	}
+	return modified, nil
+}
+
+func (r *ActionRepoImpl) Create(ctx context.Context, data *moderation.Action) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data.Id = primitive.NewObjectID()
+	r.Actions = append(r.Actions, *data)
+	return nil
+}
+
+func (r *WarningRepoImpl) Increment(ctx context.Context, userId string, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.Warnings, func(data moderation.Warning) bool { return data.UserId == userId })
+	if i < 0 {
+		r.Warnings = append(r.Warnings, moderation.Warning{Id: primitive.NewObjectID(), UserId: userId})
+		i = len(r.Warnings) - 1
+	}
+
+	r.Warnings[i].Count++
+	r.Warnings[i].LastWarnedAt = at
+	return nil
+}
+
+func (r *WarningRepoImpl) FindByUserId(ctx context.Context, userId string) (moderation.Warning, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, data := range r.Warnings {
+		if data.UserId == userId {
+			return data, nil
+		}
+	}
+	return moderation.Warning{UserId: userId}, nil
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+
+	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	"github.com/forum-gamers/nine-tails-fox/pkg/poll"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+)
+
+func NewPollRepo(s *Store) poll.PollRepo {
+	return &PollRepoImpl{s}
+}
+
+func (r *PollRepoImpl) CreateVote(ctx context.Context, data *poll.Vote) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, vote := range r.Votes {
+		if vote.PostId == data.PostId && vote.UserId == data.UserId {
+			return h.NewAppError(codes.AlreadyExists, "Already voted")
+		}
+	}
+
+	data.Id = primitive.NewObjectID()
+	r.Votes = append(r.Votes, *data)
+	return nil
+}
+
+func (r *PollRepoImpl) FindUserVotes(ctx context.Context, userId string, postIds []primitive.ObjectID) ([]poll.Vote, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	datas := []poll.Vote{}
+	for _, vote := range r.Votes {
+		if vote.UserId != userId {
+			continue
+		}
+
+		for _, id := range postIds {
+			if vote.PostId == id {
+				datas = append(datas, vote)
+				break
+			}
+		}
+	}
+	return datas, nil
+}
+
+func (r *PollRepoImpl) DeletePostVotes(ctx context.Context, postId primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	datas := r.Votes[:0]
+	for _, vote := range r.Votes {
+		if vote.PostId != postId {
+			datas = append(datas, vote)
+		}
+	}
+	r.Votes = datas
+	return nil
+}
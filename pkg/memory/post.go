@@ -0,0 +1,611 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	protobuf "github.com/forum-gamers/nine-tails-fox/generated/post"
+	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func NewPostRepo(s *Store) post.PostRepo {
+	return &PostRepoImpl{s}
+}
+
+func (r *PostRepoImpl) Create(ctx context.Context, data *post.Post) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data.Id = primitive.NewObjectID()
+	r.Posts = append(r.Posts, *data)
+	return nil
+}
+
+func (r *PostRepoImpl) FindById(ctx context.Context, id primitive.ObjectID, data *post.Post) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.findActivePost(id)
+	if !ok {
+		return errNotFound()
+	}
+	*data = r.Posts[i]
+	return nil
+}
+
+func (r *PostRepoImpl) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func (r *PostRepoImpl) DeleteOne(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i, ok := r.findPost(id); ok {
+		r.Posts = append(r.Posts[:i], r.Posts[i+1:]...)
+	}
+	return nil
+}
+
+func (r *PostRepoImpl) CreateMany(ctx context.Context, datas []any) (*mongo.InsertManyResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := &mongo.InsertManyResult{}
+	for _, data := range datas {
+		var value post.Post
+		switch v := data.(type) {
+		case post.Post:
+			value = v
+		case *post.Post:
+			value = *v
+		default:
+			return nil, fmt.Errorf("unsupported document type %T", data)
+		}
+
+		value.Id = primitive.NewObjectID()
+		r.Posts = append(r.Posts, value)
+		result.InsertedIDs = append(result.InsertedIDs, value.Id)
+	}
+	return result, nil
+}
+
+func (r *PostRepoImpl) GetPublicContent(ctx context.Context, userId string, excluded, mutedTags []string, query *protobuf.GetPostParams) ([]post.PostResponse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	since := h.StartOfDay(time.Now().UTC().AddDate(0, 0, -3))
+	var datas []post.PostResponse
+	for _, data := range r.Posts {
+		if data.Privacy != "Public" || data.CreatedAt.Before(since) || !isVisible(data) || slices.Contains(excluded, data.UserId) || hasAnyTag(data.Tags, mutedTags) {
+			continue
+		}
+		datas = append(datas, r.postResponse(data, userId))
+	}
+
+	result := paginate(datas, int(query.Page), int(query.Limit))
+	if len(result) < 1 {
+		return result, errEmptyResult()
+	}
+	return withTotal(result, len(datas)), nil
+}
+
+func (r *PostRepoImpl) GetUserPost(ctx context.Context, userId string, query *protobuf.Pagination) ([]post.PostResponse, error) {
+	return r.userPosts(userId, query, true, func(data post.Post) bool { return true })
+}
+
+func (r *PostRepoImpl) GetUserPostMedia(ctx context.Context, userId string, query *protobuf.Pagination) ([]post.PostResponse, error) {
+	return r.userPosts(userId, query, false, func(data post.Post) bool { return len(data.Media) > 0 })
+}
+
+func (r *PostRepoImpl) GetMediaItems(ctx context.Context, query post.MediaQuery) ([]post.MediaItem, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var posts []post.Post
+	for _, data := range r.Posts {
+		switch {
+		case data.UserId != query.UserId || !isVisible(data):
+		case !query.PostId.IsZero() && data.Id != query.PostId:
+		case query.PublicOnly && data.Privacy != "Public":
+		case query.From != nil && data.CreatedAt.Before(*query.From):
+		case query.To != nil && data.CreatedAt.After(*query.To):
+		default:
+			posts = append(posts, data)
+		}
+	}
+
+	sort.SliceStable(posts, func(i, j int) bool { return posts[i].CreatedAt.After(posts[j].CreatedAt) })
+
+	var datas []post.MediaItem
+	for _, data := range posts {
+		for i, media := range data.Media {
+			if query.Kind != "" && post.MediaKind(media.Type) != query.Kind {
+				continue
+			}
+
+			datas = append(datas, post.MediaItem{
+				PostId:    data.Id,
+				UserId:    data.UserId,
+				Index:     i,
+				Media:     media,
+				CreatedAt: data.CreatedAt,
+			})
+		}
+	}
+
+	result := paginate(datas, query.Page, query.Limit)
+	if len(result) < 1 {
+		return result, errEmptyResult()
+	}
+
+	for i := range result {
+		result[i].TotalData = len(datas)
+	}
+	return result, nil
+}
+
+func (r *PostRepoImpl) userPosts(userId string, query *protobuf.Pagination, pinnedFirst bool, match func(data post.Post) bool) ([]post.PostResponse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var posts []post.Post
+	for _, data := range r.Posts {
+		if data.UserId != userId || !isVisible(data) || !match(data) {
+			continue
+		}
+		posts = append(posts, data)
+	}
+
+	sort.SliceStable(posts, func(i, j int) bool {
+		if a, b := posts[i].PinnedAt, posts[j].PinnedAt; pinnedFirst && (a != nil || b != nil) {
+			return a != nil && (b == nil || a.After(*b))
+		}
+		return posts[i].CreatedAt.After(posts[j].CreatedAt)
+	})
+
+	datas := make([]post.PostResponse, 0, len(posts))
+	for _, data := range posts {
+		result := r.postResponse(data, userId)
+		result.IsPinned = pinnedFirst && data.PinnedAt != nil
+		datas = append(datas, result)
+	}
+	result := paginate(datas, int(query.Page), int(query.Limit))
+	if len(result) < 1 {
+		return result, errEmptyResult()
+	}
+	return withTotal(result, len(datas)), nil
+}
+
+func (r *PostRepoImpl) GetTopTags(ctx context.Context, query *protobuf.Pagination) ([]post.TopTags, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	since := h.StartOfDay(time.Now())
+	index := map[string]int{}
+	var datas []post.TopTags
+	for _, data := range r.Posts {
+		if data.CreatedAt.Before(since) || !isVisible(data) {
+			continue
+		}
+
+		for _, tag := range data.Tags {
+			i, ok := index[tag]
+			if !ok {
+				i = len(datas)
+				index[tag] = i
+				datas = append(datas, post.TopTags{Id: tag})
+			}
+			datas[i].Count++
+			datas[i].Posts = append(datas[i].Posts, data.Id)
+		}
+	}
+
+	sort.SliceStable(datas, func(i, j int) bool { return datas[i].Count > datas[j].Count })
+	result := paginate(datas, int(query.Page), int(query.Limit))
+	if len(result) < 1 {
+		return result, errEmptyResult()
+	}
+	return result, nil
+}
+
+func (r *PostRepoImpl) GetRelatedTags(ctx context.Context, tags, excluded []string, since time.Time, limit int) ([]post.TopTags, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	index := map[string]int{}
+	var datas []post.TopTags
+	for _, data := range r.Posts {
+		if data.Privacy != "Public" || data.CreatedAt.Before(since) || !isVisible(data) || !hasAnyTag(data.Tags, tags) {
+			continue
+		}
+
+		for _, tag := range data.Tags {
+			if slices.Contains(excluded, tag) {
+				continue
+			}
+
+			i, ok := index[tag]
+			if !ok {
+				i = len(datas)
+				index[tag] = i
+				datas = append(datas, post.TopTags{Id: tag})
+			}
+
+			if !slices.Contains(datas[i].Posts, data.Id) {
+				datas[i].Count++
+				datas[i].Posts = append(datas[i].Posts, data.Id)
+			}
+		}
+	}
+
+	sort.SliceStable(datas, func(i, j int) bool {
+		if datas[i].Count != datas[j].Count {
+			return datas[i].Count > datas[j].Count
+		}
+		return datas[i].Id < datas[j].Id
+	})
+	if len(datas) > limit {
+		datas = datas[:limit]
+	}
+
+	if len(datas) < 1 {
+		return datas, errEmptyResult()
+	}
+	return datas, nil
+}
+
+func (r *PostRepoImpl) FindPostResponseById(ctx context.Context, id primitive.ObjectID, userId string) (post.PostResponse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.findActivePost(id)
+	if !ok {
+		return post.PostResponse{}, errEmptyResult()
+	}
+	return r.postResponse(r.Posts[i], userId), nil
+}
+
+func (r *PostRepoImpl) IncrementCounter(ctx context.Context, id primitive.ObjectID, counter post.Counter, delta int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findPost(id)
+	if !ok {
+		return errNotFound()
+	}
+
+	switch counter {
+	case post.CountLike:
+		r.Posts[i].CountLike += delta
+	case post.CountComment:
+		r.Posts[i].CountComment += delta
+	case post.CountShare:
+		r.Posts[i].CountShare += delta
+	}
+	return nil
+}
+
+func (r *PostRepoImpl) ReconcileCounters(ctx context.Context) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var modified int64
+	for i, data := range r.Posts {
+		var countLike, countComment, countShare int
+		for _, like := range r.Likes {
+			if like.PostId == data.Id {
+				countLike++
+			}
+		}
+
+		for _, share := range r.Shares {
+			if share.PostId == data.Id {
+				countShare++
+			}
+		}
+
+		for _, comment := range r.Comments {
+			if comment.PostId == data.Id && isActiveComment(comment) {
+				countComment += 1 + len(activeReplies(comment.Reply))
+			}
+		}
+
+		if data.CountLike != countLike || data.CountComment != countComment || data.CountShare != countShare {
+			r.Posts[i].CountLike, r.Posts[i].CountComment, r.Posts[i].CountShare = countLike, countComment, countShare
+			modified++
+		}
+	}
+	return modified, nil
+}
+
+func (r *PostRepoImpl) SoftDelete(ctx context.Context, id primitive.ObjectID, userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findPost(id)
+	if !ok || r.Posts[i].DeletedAt != nil {
+		return errNotFound()
+	}
+
+	now := time.Now()
+	r.Posts[i].DeletedAt = &now
+	r.Posts[i].DeletedBy = userId
+	r.Posts[i].PinnedAt = nil
+	return nil
+}
+
+func (r *PostRepoImpl) Restore(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findPost(id)
+	if !ok || r.Posts[i].DeletedAt == nil {
+		return errNotFound()
+	}
+
+	r.Posts[i].DeletedAt = nil
+	r.Posts[i].DeletedBy = ""
+	return nil
+}
+
+func (r *PostRepoImpl) FindDeletedById(ctx context.Context, id primitive.ObjectID, data *post.Post) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.findPost(id)
+	if !ok || r.Posts[i].DeletedAt == nil {
+		return errNotFound()
+	}
+	*data = r.Posts[i]
+	return nil
+}
+
+func (r *PostRepoImpl) GetTrash(ctx context.Context, userId string, since time.Time, query *protobuf.Pagination) ([]post.PostResponse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var datas []post.PostResponse
+	for _, data := range r.Posts {
+		if data.UserId != userId || data.DeletedBy != userId || data.DeletedAt == nil || data.DeletedAt.Before(since) {
+			continue
+		}
+
+		result := r.postResponse(data, userId)
+		result.IsLiked, result.IsShared = false, false
+		result.DeletedAt = data.DeletedAt
+		datas = append(datas, result)
+	}
+
+	sort.SliceStable(datas, func(i, j int) bool { return datas[i].DeletedAt.After(*datas[j].DeletedAt) })
+	result := paginate(datas, int(query.Page), int(query.Limit))
+	if len(result) < 1 {
+		return result, errEmptyResult()
+	}
+	return withTotal(result, len(datas)), nil
+}
+
+func (r *PostRepoImpl) FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]post.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var datas []post.Post
+	for _, data := range r.Posts {
+		if data.DeletedAt != nil && data.DeletedAt.Before(before) {
+			datas = append(datas, data)
+		}
+	}
+
+	sort.SliceStable(datas, func(i, j int) bool { return datas[i].DeletedAt.Before(*datas[j].DeletedAt) })
+	return paginate(datas, 1, limit), nil
+}
+
+func (r *PostRepoImpl) findDraft(id primitive.ObjectID) (int, bool) {
+	i, ok := r.findPost(id)
+	if !ok || r.Posts[i].DeletedAt != nil || !isDraft(r.Posts[i]) {
+		return -1, false
+	}
+	return i, true
+}
+
+func (r *PostRepoImpl) FindDraftById(ctx context.Context, id primitive.ObjectID, data *post.Post) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.findDraft(id)
+	if !ok {
+		return errNotFound()
+	}
+	*data = r.Posts[i]
+	return nil
+}
+
+func (r *PostRepoImpl) GetDrafts(ctx context.Context, userId string, query *protobuf.Pagination) ([]post.PostResponse, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var datas []post.PostResponse
+	for _, data := range r.Posts {
+		if data.UserId != userId || data.DeletedAt != nil || !isDraft(data) {
+			continue
+		}
+
+		result := r.postResponse(data, userId)
+		result.Status = data.Status
+		result.PublishAt = data.PublishAt
+		datas = append(datas, result)
+	}
+
+	sort.SliceStable(datas, func(i, j int) bool { return datas[i].UpdatedAt.After(datas[j].UpdatedAt) })
+	result := paginate(datas, int(query.Page), int(query.Limit))
+	if len(result) < 1 {
+		return result, errEmptyResult()
+	}
+	return withTotal(result, len(datas)), nil
+}
+
+func (r *PostRepoImpl) UpdateDraft(ctx context.Context, data *post.Post) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findDraft(data.Id)
+	if !ok {
+		return errNotFound()
+	}
+
+	current := &r.Posts[i]
+	current.Text = data.Text
+	current.Media = data.Media
+	current.AllowComment = data.AllowComment
+	current.Privacy = data.Privacy
+	current.Tags = data.Tags
+	current.Previews = data.Previews
+	current.Status = data.Status
+	current.PublishAt = data.PublishAt
+	current.UpdatedAt = data.UpdatedAt
+	return nil
+}
+
+func (r *PostRepoImpl) Publish(ctx context.Context, data *post.Post) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findDraft(data.Id)
+	if !ok {
+		return errNotFound()
+	}
+
+	current := &r.Posts[i]
+	current.Status = post.Published
+	current.Text = data.Text
+	current.Tags = data.Tags
+	current.Previews = data.Previews
+	current.CreatedAt = data.CreatedAt
+	current.UpdatedAt = data.UpdatedAt
+	current.PublishAt = nil
+	if data.HiddenAt != nil {
+		current.HiddenAt, current.HiddenBy = data.HiddenAt, data.HiddenBy
+	}
+	return nil
+}
+
+func (r *PostRepoImpl) FindDuePosts(ctx context.Context, now time.Time, after primitive.ObjectID, limit int) ([]post.Post, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var datas []post.Post
+	for _, data := range r.Posts {
+		if data.Status == post.Scheduled && data.DeletedAt == nil && data.PublishAt != nil && !data.PublishAt.After(now) && bytes.Compare(data.Id[:], after[:]) > 0 {
+			datas = append(datas, data)
+		}
+	}
+
+	sort.SliceStable(datas, func(i, j int) bool { return bytes.Compare(datas[i].Id[:], datas[j].Id[:]) < 0 })
+	return paginate(datas, 1, limit), nil
+}
+
+func (r *PostRepoImpl) Pin(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findActivePost(id)
+	if !ok {
+		return errNotFound()
+	}
+
+	r.Posts[i].PinnedAt = &at
+	return nil
+}
+
+func (r *PostRepoImpl) Unpin(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findPost(id)
+	if !ok {
+		return errNotFound()
+	}
+
+	r.Posts[i].PinnedAt = nil
+	return nil
+}
+
+func (r *PostRepoImpl) CountPinned(ctx context.Context, userId string) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, data := range r.Posts {
+		if data.UserId == userId && data.PinnedAt != nil && isVisible(data) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *PostRepoImpl) AddPollVotes(ctx context.Context, id primitive.ObjectID, options []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findActivePost(id)
+	if !ok || r.Posts[i].Poll == nil {
+		return errNotFound()
+	}
+
+	poll := clonePoll(r.Posts[i].Poll)
+	poll.TotalVotes++
+	for _, option := range options {
+		poll.Options[option].Count++
+	}
+	r.Posts[i].Poll = poll
+	return nil
+}
+
+func (r *PostRepoImpl) Hide(ctx context.Context, id primitive.ObjectID, userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findActivePost(id)
+	if !ok {
+		return errNotFound()
+	}
+
+	now := time.Now()
+	r.Posts[i].HiddenAt = &now
+	r.Posts[i].HiddenBy = userId
+	r.Posts[i].PinnedAt = nil
+	return nil
+}
+
+func (r *PostRepoImpl) FindHiddenById(ctx context.Context, id primitive.ObjectID, data *post.Post) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	i, ok := r.findPost(id)
+	if !ok || r.Posts[i].DeletedAt != nil || r.Posts[i].HiddenAt == nil {
+		return errNotFound()
+	}
+	*data = r.Posts[i]
+	return nil
+}
+
+func (r *PostRepoImpl) Unhide(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i, ok := r.findPost(id)
+	if !ok || r.Posts[i].DeletedAt != nil || r.Posts[i].HiddenAt == nil {
+		return errNotFound()
+	}
+
+	r.Posts[i].HiddenAt = nil
+	r.Posts[i].HiddenBy = ""
+	return nil
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/preference"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewPreferenceRepo(s *Store) preference.PreferenceRepo {
+	return &PreferenceRepoImpl{s}
+}
+
+func (r *PreferenceRepoImpl) Create(ctx context.Context, userId string) (preference.UserPreference, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.create(userId), nil
+}
+
+func (r *PreferenceRepoImpl) create(userId string) preference.UserPreference {
+	data := preference.NewUserPreference(userId, time.Now())
+	data.Id = primitive.NewObjectID()
+	r.Preferences = append(r.Preferences, data)
+	return data
+}
+
+func (r *PreferenceRepoImpl) FindByUserId(ctx context.Context, userId string) (preference.UserPreference, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, data := range r.Preferences {
+		if data.UserId == userId {
+			return data, nil
+		}
+	}
+	return preference.NewUserPreference(userId, time.Now()), nil
+}
+
+func (r *PreferenceRepoImpl) UpdateTags(ctx context.Context, userId string, tags []preference.TagPreference) error {
+	return r.upsert(userId, true, func(data *preference.UserPreference) {
+		data.Tags = tags
+	})
+}
+
+func (r *PreferenceRepoImpl) MuteTag(ctx context.Context, userId, tag string) error {
+	return r.upsert(userId, true, func(data *preference.UserPreference) {
+		if !slices.Contains(data.MutedTags, tag) {
+			data.MutedTags = append(slices.Clone(data.MutedTags), tag)
+		}
+	})
+}
+
+func (r *PreferenceRepoImpl) UnmuteTag(ctx context.Context, userId, tag string) error {
+	return r.upsert(userId, false, func(data *preference.UserPreference) {
+		data.MutedTags = slices.DeleteFunc(slices.Clone(data.MutedTags), func(muted string) bool { return muted == tag })
+	})
+}
+
+func (r *PreferenceRepoImpl) Reset(ctx context.Context, userId string) error {
+	return r.upsert(userId, false, func(data *preference.UserPreference) {
+		data.Tags, data.MutedTags = []preference.TagPreference{}, []string{}
+	})
+}
+
+// upsert applies fn to the user's preference, creating it first when create
+// is set.
+func (r *PreferenceRepoImpl) upsert(userId string, create bool, fn func(data *preference.UserPreference)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := slices.IndexFunc(r.Preferences, func(data preference.UserPreference) bool { return data.UserId == userId })
+	if i < 0 {
+		if !create {
+			return nil
+		}
+		r.create(userId)
+		i = len(r.Preferences) - 1
+	}
+
+	fn(&r.Preferences[i])
+	r.Preferences[i].UpdatedAt = time.Now()
+	return nil
+}
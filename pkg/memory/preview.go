@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/preview"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewPreviewRepo(s *Store) preview.PreviewRepo {
+	return &PreviewRepoImpl{s}
+}
+
+func (r *PreviewRepoImpl) FindByUrls(ctx context.Context, urls []string) ([]preview.Preview, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	result := []preview.Preview{}
+	for _, data := range r.Previews {
+		if slices.Contains(urls, data.Url) && data.ExpiresAt.After(now) {
+			result = append(result, data)
+		}
+	}
+	return result, nil
+}
+
+func (r *PreviewRepoImpl) Upsert(ctx context.Context, data *preview.Preview) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.Previews {
+		if existing.Url == data.Url {
+			data.Id = existing.Id
+			r.Previews[i] = *data
+			return nil
+		}
+	}
+
+	data.Id = primitive.NewObjectID()
+	r.Previews = append(r.Previews, *data)
+	return nil
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/relation"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewRelationRepo(s *Store) relation.RelationRepo {
+	return &RelationRepoImpl{s}
+}
+
+func (r *RelationRepoImpl) Create(ctx context.Context, data *relation.Relation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.Relations {
+		if existing.UserId == data.UserId && existing.TargetId == data.TargetId && existing.Type == data.Type {
+			return errConflict()
+		}
+	}
+
+	data.Id = primitive.NewObjectID()
+	r.Relations = append(r.Relations, *data)
+	return nil
+}
+
+func (r *RelationRepoImpl) FindOne(ctx context.Context, userId, targetId, relationType string) (relation.Relation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, data := range r.Relations {
+		if data.UserId == userId && data.TargetId == targetId && data.Type == relationType {
+			return data, nil
+		}
+	}
+	return relation.Relation{}, errNotFound()
+}
+
+func (r *RelationRepoImpl) Delete(ctx context.Context, userId, targetId, relationType string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, data := range r.Relations {
+		if data.UserId == userId && data.TargetId == targetId && data.Type == relationType {
+			r.Relations = append(r.Relations[:i], r.Relations[i+1:]...)
+			return nil
+		}
+	}
+	return errNotFound()
+}
+
+func (r *RelationRepoImpl) IsBlocked(ctx context.Context, userId, targetId string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, data := range r.Relations {
+		if data.UserId == userId && data.TargetId == targetId && data.Type == relation.Block {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *RelationRepoImpl) FindHiddenUserIds(ctx context.Context, userId string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ids := []string{}
+	for _, data := range r.Relations {
+		switch {
+		case data.UserId == userId:
+			ids = append(ids, data.TargetId)
+		case data.TargetId == userId && data.Type == relation.Block:
+			ids = append(ids, data.UserId)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,26 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/share"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewShareRepo(s *Store) share.ShareRepo {
+	return &ShareRepoImpl{s}
+}
+
+func (r *ShareRepoImpl) DeleteMany(ctx context.Context, postId primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	shares := r.Shares[:0]
+	for _, data := range r.Shares {
+		if data.PostId != postId {
+			shares = append(shares, data)
+		}
+	}
+	r.Shares = shares
+	return nil
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/spam"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewFingerprintRepo(s *Store) spam.FingerprintRepo {
+	return &FingerprintRepoImpl{s}
+}
+
+func (r *FingerprintRepoImpl) Create(ctx context.Context, data *spam.Fingerprint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data.Id = primitive.NewObjectID()
+	r.Fingerprints = append(r.Fingerprints, *data)
+	return nil
+}
+
+func (r *FingerprintRepoImpl) CountSince(ctx context.Context, userId, hash string, since time.Time) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, data := range r.Fingerprints {
+		if data.UserId == userId && !data.CreatedAt.Before(since) && (hash == "" || data.Hash == hash) {
+			count++
+		}
+	}
+	return count, nil
+}
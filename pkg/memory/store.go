@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"slices"
+
+	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+)
+
+func NewStore() *Store {
+	return &Store{}
+}
+
+func errNotFound() error {
+	return h.NewAppError(codes.NotFound, "Data not found")
+}
+
+func errEmptyResult() error {
+	return h.NewAppError(codes.NotFound, "data not found")
+}
+
+func errConflict() error {
+	return h.NewAppError(codes.AlreadyExists, "Conflict")
+}
+
+func paginate[T any](datas []T, page, limit int) []T {
+	start := (page - 1) * limit
+	if start < 0 {
+		start = 0
+	}
+	if start >= len(datas) {
+		return []T{}
+	}
+
+	end := start + limit
+	if limit < 0 || end > len(datas) {
+		end = len(datas)
+	}
+	return datas[start:end]
+}
+
+func (s *Store) findPost(id primitive.ObjectID) (int, bool) {
+	for i, data := range s.Posts {
+		if data.Id == id {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (s *Store) findActivePost(id primitive.ObjectID) (int, bool) {
+	i, ok := s.findPost(id)
+	if !ok || !isVisible(s.Posts[i]) {
+		return -1, false
+	}
+	return i, true
+}
+
+func isVisible(data post.Post) bool {
+	return data.DeletedAt == nil && data.HiddenAt == nil && !isDraft(data)
+}
+
+func isDraft(data post.Post) bool {
+	return data.Status == post.Draft || data.Status == post.Scheduled
+}
+
+func hasAnyTag(tags, muted []string) bool {
+	for _, tag := range tags {
+		if slices.Contains(muted, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+func isActiveComment(data comment.Comment) bool {
+	return data.DeletedAt == nil && data.HiddenAt == nil
+}
+
+func isActiveReply(data comment.ReplyComment) bool {
+	return data.DeletedAt == nil && data.HiddenAt == nil
+}
+
+func activeReplies(replies []comment.ReplyComment) []comment.ReplyComment {
+	result := make([]comment.ReplyComment, 0, len(replies))
+	for _, reply := range replies {
+		if isActiveReply(reply) {
+			result = append(result, reply)
+		}
+	}
+	return result
+}
+
+func (s *Store) postResponse(data post.Post, userId string) post.PostResponse {
+	result := post.PostResponse{
+		Id:           data.Id,
+		UserId:       data.UserId,
+		Text:         data.Text,
+		Media:        data.Media,
+		AllowComment: data.AllowComment,
+		CreatedAt:    data.CreatedAt,
+		UpdatedAt:    data.UpdatedAt,
+		Tags:         data.Tags,
+		Privacy:      data.Privacy,
+		CountLike:    data.CountLike,
+		CountComment: data.CountComment,
+		CountShare:   data.CountShare,
+		Poll:         clonePoll(data.Poll),
+		Previews:     data.Previews,
+	}
+
+	for _, like := range s.Likes {
+		if like.PostId == data.Id && like.UserId == userId {
+			result.IsLiked = true
+			break
+		}
+	}
+
+	for _, share := range s.Shares {
+		if share.PostId == data.Id && share.UserId == userId {
+			result.IsShared = true
+			break
+		}
+	}
+	return result
+}
+
+func withTotal(datas []post.PostResponse, total int) []post.PostResponse {
+	for i := range datas {
+		datas[i].TotalData = total
+	}
+	return datas
+}
+
+// clonePoll copies a poll so callers can annotate or count votes on the copy
+// without writing through to the stored post.
+func clonePoll(poll *post.Poll) *post.Poll {
+	if poll == nil {
+		return nil
+	}
+
+	result := *poll
+	result.Options = append([]post.PollOption(nil), poll.Options...)
+	return &result
+}
@@ -0,0 +1,84 @@
+package moderation
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/pkg/user"
+	"github.com/forum-gamers/nine-tails-fox/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	TargetPost    = "Post"
+	TargetComment = "Comment"
+	TargetReply   = "Reply"
+)
+
+const (
+	Open      = "Open"
+	Resolved  = "Resolved"
+	Dismissed = "Dismissed"
+)
+
+const (
+	ActionDismiss = "Dismiss"
+	ActionHide    = "Hide"
+	ActionDelete  = "Delete"
+	ActionWarn    = "Warn"
+	// Release makes hidden or quarantined content visible again
+	ActionRelease = "Release"
+)
+
+const MAXREPORTTEXTLENGTH = 500
+
+// content flagged by the system is reported under this reporter and reason,
+// the reason cannot be picked by users
+const (
+	SYSTEMREPORTER = "system"
+	ReasonFlagged  = "Flagged"
+)
+
+var Reasons = []string{"Spam", "Harassment", "HateSpeech", "Violence", "Sexual", "Cheating", "Misinformation", "Other"}
+
+type ReportRepo interface {
+	Create(ctx context.Context, data *Report) error
+	GetQueue(ctx context.Context, query QueueQuery) ([]QueueItem, error)
+	Resolve(ctx context.Context, targetType string, targetId primitive.ObjectID, status, moderatorId string, at time.Time) (int64, error)
+}
+
+type ReportRepoImpl struct {
+	base.BaseRepo
+	utils.QueryUtils
+}
+
+type ActionRepo interface {
+	Create(ctx context.Context, data *Action) error
+}
+
+type ActionRepoImpl struct {
+	base.BaseRepo
+}
+
+type WarningRepo interface {
+	Increment(ctx context.Context, userId string, at time.Time) error
+	FindByUserId(ctx context.Context, userId string) (Warning, error)
+}
+
+type WarningRepoImpl struct {
+	base.BaseRepo
+}
+
+type ModerationService interface {
+	IsModerator(data user.User) bool
+	IsValidTarget(targetType string) bool
+	IsValidReason(reason string) bool
+	IsValidAction(action string) bool
+	CreateReportPayload(target Target, reporterId, reason, text string) Report
+	CreateActionPayload(target Target, moderatorId, action, note string, resolved int64) Action
+	CreateFlagPayload(target Target, rules []string) Report
+	CreateQuarantinePayload(target Target, score int, reasons []string) Report
+}
+
+type ModerationServiceImpl struct{}
@@ -0,0 +1,66 @@
+package moderation
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Target identifies reported content. CommentId is only set for replies,
+// which live inside their comment document.
+type Target struct {
+	TargetType string             `json:"targetType" bson:"targetType"`
+	TargetId   primitive.ObjectID `json:"targetId" bson:"targetId"`
+	CommentId  primitive.ObjectID `json:"commentId,omitempty" bson:"commentId,omitempty"`
+	PostId     primitive.ObjectID `json:"postId" bson:"postId"`
+	AuthorId   string             `json:"authorId" bson:"authorId"`
+}
+
+type Report struct {
+	Id         primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Target     `bson:",inline"`
+	ReporterId string     `json:"reporterId" bson:"reporterId"`
+	Reason     string     `json:"reason" bson:"reason"`
+	Text       string     `json:"text" bson:"text"`
+	Status     string     `json:"status" bson:"status"`
+	CreatedAt  time.Time  `json:"createdAt" bson:"createdAt"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty" bson:"resolvedAt,omitempty"`
+	ResolvedBy string     `json:"resolvedBy,omitempty" bson:"resolvedBy,omitempty"`
+}
+
+type Action struct {
+	Id              primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Target          `bson:",inline"`
+	ModeratorId     string    `json:"moderatorId" bson:"moderatorId"`
+	Action          string    `json:"action" bson:"action"`
+	Note            string    `json:"note" bson:"note"`
+	ResolvedReports int64     `json:"resolvedReports" bson:"resolvedReports"`
+	CreatedAt       time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// Warning counts the Warn actions taken against a user's content.
+type Warning struct {
+	Id           primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	UserId       string             `json:"userId" bson:"userId"`
+	Count        int                `json:"count" bson:"count"`
+	LastWarnedAt time.Time          `json:"lastWarnedAt" bson:"lastWarnedAt"`
+}
+
+type QueueQuery struct {
+	Page       int
+	Limit      int
+	Status     string
+	TargetType string
+	Reason     string
+}
+
+// QueueItem groups every report filed against the same piece of content.
+type QueueItem struct {
+	Target          `bson:",inline"`
+	ReportCount     int       `json:"reportCount" bson:"reportCount"`
+	Reasons         []string  `json:"reasons" bson:"reasons"`
+	Texts           []string  `json:"texts" bson:"texts"`
+	FirstReportedAt time.Time `json:"firstReportedAt" bson:"firstReportedAt"`
+	LastReportedAt  time.Time `json:"lastReportedAt" bson:"lastReportedAt"`
+	TotalData       int       `json:"totalData" bson:"totalData"`
+}
@@ -0,0 +1,201 @@
+package moderation
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
+	"github.com/forum-gamers/nine-tails-fox/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func NewReportRepo(db database.Database, q utils.QueryUtils) ReportRepo {
+	return &ReportRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Report)), q}
+}
+
+func NewActionRepo(db database.Database) ActionRepo {
+	return &ActionRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.ModerationAction))}
+}
+
+func NewWarningRepo(db database.Database) WarningRepo {
+	return &WarningRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Warning))}
+}
+
+func (r *ReportRepoImpl) Create(ctx context.Context, data *Report) error {
+	ctx, span := tracing.Start(ctx, "ReportRepo.Create")
+	defer span.End()
+
+	id, created, err := r.InsertIfNotExists(ctx, bson.M{
+		"targetType": data.TargetType,
+		"targetId":   data.TargetId,
+		"reporterId": data.ReporterId,
+		"status":     Open,
+	}, data)
+	if err != nil {
+		return err
+	}
+
+	if !created {
+		return h.NewAppError(codes.AlreadyExists, "Already reported")
+	}
+	data.Id = id
+	return nil
+}
+
+func (r *ReportRepoImpl) GetQueue(ctx context.Context, query QueueQuery) ([]QueueItem, error) {
+	ctx, span := tracing.Start(ctx, "ReportRepo.GetQueue")
+	defer span.End()
+
+	match := bson.D{{Key: "status", Value: query.Status}}
+	if query.TargetType != "" {
+		match = append(match, bson.E{Key: "targetType", Value: query.TargetType})
+	}
+
+	if query.Reason != "" {
+		match = append(match, bson.E{Key: "reason", Value: query.Reason})
+	}
+
+	curr, err := r.Aggregations(ctx, bson.A{
+		bson.D{{Key: "$match", Value: match}},
+		bson.D{
+			{Key: "$group",
+				Value: bson.D{
+					{Key: "_id", Value: bson.D{{Key: "targetType", Value: "$targetType"}, {Key: "targetId", Value: "$targetId"}}},
+					{Key: "commentId", Value: bson.D{{Key: "$first", Value: "$commentId"}}},
+					{Key: "postId", Value: bson.D{{Key: "$first", Value: "$postId"}}},
+					{Key: "authorId", Value: bson.D{{Key: "$first", Value: "$authorId"}}},
+					{Key: "reportCount", Value: bson.D{{Key: "$sum", Value: 1}}},
+					{Key: "reasons", Value: bson.D{{Key: "$addToSet", Value: "$reason"}}},
+					{Key: "texts", Value: bson.D{{Key: "$push", Value: "$text"}}},
+					{Key: "firstReportedAt", Value: bson.D{{Key: "$min", Value: "$createdAt"}}},
+					{Key: "lastReportedAt", Value: bson.D{{Key: "$max", Value: "$createdAt"}}},
+				},
+			},
+		},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "reportCount", Value: -1}, {Key: "lastReportedAt", Value: -1}}}},
+		bson.D{
+			{Key: "$facet",
+				Value: bson.D{
+					{Key: "total",
+						Value: bson.A{
+							bson.D{{Key: "$count", Value: "total"}},
+						},
+					},
+					{Key: "datas",
+						Value: bson.A{
+							r.NewSkip((query.Page - 1) * query.Limit),
+							r.NewLimit(query.Limit),
+						},
+					},
+				},
+			},
+		},
+		r.NewRawUnwind("$datas"),
+		r.NewRawUnwind("$total"),
+		bson.D{
+			{Key: "$project",
+				Value: bson.D{
+					{Key: "_id", Value: 0},
+					{Key: "targetType", Value: "$datas._id.targetType"},
+					{Key: "targetId", Value: "$datas._id.targetId"},
+					{Key: "commentId", Value: "$datas.commentId"},
+					{Key: "postId", Value: "$datas.postId"},
+					{Key: "authorId", Value: "$datas.authorId"},
+					{Key: "reportCount", Value: "$datas.reportCount"},
+					{Key: "reasons", Value: "$datas.reasons"},
+					{Key: "texts", Value: bson.D{
+						{Key: "$filter", Value: bson.D{
+							{Key: "input", Value: "$datas.texts"},
+							{Key: "cond", Value: bson.D{{Key: "$ne", Value: bson.A{"$$this", ""}}}},
+						}},
+					}},
+					{Key: "firstReportedAt", Value: "$datas.firstReportedAt"},
+					{Key: "lastReportedAt", Value: "$datas.lastReportedAt"},
+					{Key: "totalData", Value: "$total.total"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	var datas []QueueItem
+	for curr.Next(ctx) {
+		var data QueueItem
+		if err := curr.Decode(&data); err != nil {
+			return nil, err
+		}
+		datas = append(datas, data)
+	}
+
+	if len(datas) < 1 {
+		return datas, h.NewAppError(codes.NotFound, "data not found")
+	}
+	return datas, nil
+}
+
+func (r *ReportRepoImpl) Resolve(ctx context.Context, targetType string, targetId primitive.ObjectID, status, moderatorId string, at time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "ReportRepo.Resolve")
+	defer span.End()
+
+	result, err := r.UpdateMany(ctx, bson.M{
+		"targetType": targetType,
+		"targetId":   targetId,
+		"status":     Open,
+	}, bson.M{
+		"$set": bson.M{
+			"status":     status,
+			"resolvedAt": at,
+			"resolvedBy": moderatorId,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *ActionRepoImpl) Create(ctx context.Context, data *Action) error {
+	ctx, span := tracing.Start(ctx, "ActionRepo.Create")
+	defer span.End()
+
+	id, err := r.BaseRepo.Create(ctx, data)
+	if err != nil {
+		return err
+	}
+	data.Id = id
+	return nil
+}
+
+func (r *WarningRepoImpl) Increment(ctx context.Context, userId string, at time.Time) error {
+	ctx, span := tracing.Start(ctx, "WarningRepo.Increment")
+	defer span.End()
+
+	_, err := r.UpsertOne(ctx, bson.M{"userId": userId}, bson.M{
+		"$inc": bson.M{"count": 1},
+		"$set": bson.M{"lastWarnedAt": at},
+	})
+	return err
+}
+
+// FindByUserId returns an empty warning for users that were never warned.
+func (r *WarningRepoImpl) FindByUserId(ctx context.Context, userId string) (data Warning, err error) {
+	ctx, span := tracing.Start(ctx, "WarningRepo.FindByUserId")
+	defer span.End()
+
+	err = r.FindOneByQuery(ctx, bson.M{"userId": userId}, &data)
+	if err != nil {
+		if e, ok := status.FromError(err); ok && e.Code() == codes.NotFound {
+			return Warning{UserId: userId}, nil
+		}
+	}
+	return
+}
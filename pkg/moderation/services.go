@@ -0,0 +1,60 @@
+package moderation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/user"
+)
+
+func NewModerationService() ModerationService {
+	return &ModerationServiceImpl{}
+}
+
+func (s *ModerationServiceImpl) IsModerator(data user.User) bool {
+	return data.AccountType == "Admin" || data.AccountType == "Moderator"
+}
+
+func (s *ModerationServiceImpl) IsValidTarget(targetType string) bool {
+	return slices.Contains([]string{TargetPost, TargetComment, TargetReply}, targetType)
+}
+
+func (s *ModerationServiceImpl) IsValidReason(reason string) bool {
+	return slices.Contains(Reasons, reason)
+}
+
+func (s *ModerationServiceImpl) IsValidAction(action string) bool {
+	return slices.Contains([]string{ActionDismiss, ActionHide, ActionDelete, ActionWarn, ActionRelease}, action)
+}
+
+func (s *ModerationServiceImpl) CreateReportPayload(target Target, reporterId, reason, text string) Report {
+	return Report{
+		Target:     target,
+		ReporterId: reporterId,
+		Reason:     reason,
+		Text:       text,
+		Status:     Open,
+		CreatedAt:  time.Now(),
+	}
+}
+
+func (s *ModerationServiceImpl) CreateActionPayload(target Target, moderatorId, action, note string, resolved int64) Action {
+	return Action{
+		Target:          target,
+		ModeratorId:     moderatorId,
+		Action:          action,
+		Note:            note,
+		ResolvedReports: resolved,
+		CreatedAt:       time.Now(),
+	}
+}
+
+func (s *ModerationServiceImpl) CreateFlagPayload(target Target, rules []string) Report {
+	return s.CreateReportPayload(target, SYSTEMREPORTER, ReasonFlagged, strings.Join(rules, ","))
+}
+
+func (s *ModerationServiceImpl) CreateQuarantinePayload(target Target, score int, reasons []string) Report {
+	return s.CreateReportPayload(target, SYSTEMREPORTER, "Spam", fmt.Sprintf("spam score %d : %s", score, strings.Join(reasons, ",")))
+}
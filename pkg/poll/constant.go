@@ -0,0 +1,36 @@
+package poll
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	MINOPTIONS      = 2
+	MAXOPTIONS      = 10
+	MAXOPTIONLENGTH = 100
+)
+
+type PollRepo interface {
+	CreateVote(ctx context.Context, data *Vote) error
+	FindUserVotes(ctx context.Context, userId string, postIds []primitive.ObjectID) ([]Vote, error)
+	DeletePostVotes(ctx context.Context, postId primitive.ObjectID) error
+}
+
+type PollRepoImpl struct {
+	base.BaseRepo
+	utils.QueryUtils
+}
+
+type PollService interface {
+	CreatePayload(options []string, multipleChoice, hideResults bool, closesAt *time.Time) *post.Poll
+	CreateVotePayload(postId primitive.ObjectID, userId string, options []int) Vote
+	ApplyViewer(ctx context.Context, userId string, datas []post.PostResponse) error
+}
+
+type PollServiceImpl struct{ Repo PollRepo }
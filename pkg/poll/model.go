@@ -0,0 +1,15 @@
+package poll
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Vote struct {
+	Id        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	PostId    primitive.ObjectID `json:"postId" bson:"postId"`
+	UserId    string             `json:"userId" bson:"userId"`
+	Options   []int              `json:"options" bson:"options"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
@@ -0,0 +1,58 @@
+package poll
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
+	"github.com/forum-gamers/nine-tails-fox/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+)
+
+func NewPollRepo(db database.Database, q utils.QueryUtils) PollRepo {
+	return &PollRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.PollVote)), q}
+}
+
+func (r *PollRepoImpl) CreateVote(ctx context.Context, data *Vote) error {
+	ctx, span := tracing.Start(ctx, "PollRepo.CreateVote")
+	defer span.End()
+
+	id, created, err := r.InsertIfNotExists(ctx, bson.M{"postId": data.PostId, "userId": data.UserId}, data)
+	if err != nil {
+		return err
+	}
+
+	if !created {
+		return h.NewAppError(codes.AlreadyExists, "Already voted")
+	}
+	data.Id = id
+	return nil
+}
+
+func (r *PollRepoImpl) FindUserVotes(ctx context.Context, userId string, postIds []primitive.ObjectID) ([]Vote, error) {
+	ctx, span := tracing.Start(ctx, "PollRepo.FindUserVotes")
+	defer span.End()
+
+	cursor, err := r.FindByQuery(ctx, bson.M{"userId": userId, "postId": bson.M{"$in": postIds}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	datas := []Vote{}
+	if err := cursor.All(ctx, &datas); err != nil {
+		return nil, err
+	}
+	return datas, nil
+}
+
+func (r *PollRepoImpl) DeletePostVotes(ctx context.Context, postId primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "PollRepo.DeletePostVotes")
+	defer span.End()
+
+	return r.DeleteManyByQuery(ctx, bson.M{"postId": postId})
+}
@@ -0,0 +1,84 @@
+package poll
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func NewPollService(repo PollRepo) PollService {
+	return &PollServiceImpl{repo}
+}
+
+func (s *PollServiceImpl) CreatePayload(options []string, multipleChoice, hideResults bool, closesAt *time.Time) *post.Poll {
+	pollOptions := make([]post.PollOption, 0, len(options))
+	for _, option := range options {
+		pollOptions = append(pollOptions, post.PollOption{Text: option})
+	}
+
+	return &post.Poll{
+		Options:        pollOptions,
+		MultipleChoice: multipleChoice,
+		ClosesAt:       closesAt,
+		HideResults:    hideResults,
+	}
+}
+
+func (s *PollServiceImpl) CreateVotePayload(postId primitive.ObjectID, userId string, options []int) Vote {
+	return Vote{
+		PostId:    postId,
+		UserId:    userId,
+		Options:   options,
+		CreatedAt: time.Now(),
+	}
+}
+
+// ApplyViewer fills in the viewer's own votes and blanks out the tallies of
+// polls whose author hides results until the viewer has voted. The author
+// always sees the results, and everyone does once the poll has closed.
+func (s *PollServiceImpl) ApplyViewer(ctx context.Context, userId string, datas []post.PostResponse) error {
+	ids := make([]primitive.ObjectID, 0)
+	for _, data := range datas {
+		if data.Poll != nil {
+			ids = append(ids, data.Id)
+		}
+	}
+
+	if len(ids) < 1 {
+		return nil
+	}
+
+	votes, err := s.Repo.FindUserVotes(ctx, userId, ids)
+	if err != nil {
+		return err
+	}
+
+	voted := make(map[primitive.ObjectID][]int, len(votes))
+	for _, vote := range votes {
+		voted[vote.PostId] = vote.Options
+	}
+
+	now := time.Now()
+	for _, data := range datas {
+		poll := data.Poll
+		if poll == nil {
+			continue
+		}
+
+		poll.MyVotes = voted[data.Id]
+		if poll.HideResults && poll.MyVotes == nil && data.UserId != userId && !IsClosed(poll, now) {
+			poll.ResultsHidden = true
+			poll.TotalVotes = 0
+			for i := range poll.Options {
+				poll.Options[i].Count = 0
+			}
+		}
+	}
+	return nil
+}
+
+func IsClosed(poll *post.Poll, now time.Time) bool {
+	return poll.ClosesAt != nil && !poll.ClosesAt.After(now)
+}
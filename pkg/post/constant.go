@@ -2,6 +2,7 @@ package post
 
 import (
 	"context"
+	"time"
 
 	protobuf "github.com/forum-gamers/nine-tails-fox/generated/post"
 	"github.com/forum-gamers/nine-tails-fox/pkg/base"
@@ -13,16 +14,51 @@ import (
 type PostRepo interface {
 	Create(ctx context.Context, data *Post) error
 	FindById(ctx context.Context, id primitive.ObjectID, data *Post) error
-	GetSession() (mongo.Session, error)
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 	DeleteOne(ctx context.Context, id primitive.ObjectID) error
 	CreateMany(ctx context.Context, datas []any) (*mongo.InsertManyResult, error)
-	GetPublicContent(ctx context.Context, userId string, query *protobuf.GetPostParams) ([]PostResponse, error)
+	GetPublicContent(ctx context.Context, userId string, excluded, mutedTags []string, query *protobuf.GetPostParams) ([]PostResponse, error)
 	GetUserPost(ctx context.Context, userId string, query *protobuf.Pagination) ([]PostResponse, error)
 	GetUserPostMedia(ctx context.Context, userId string, query *protobuf.Pagination) ([]PostResponse, error)
+	GetMediaItems(ctx context.Context, query MediaQuery) ([]MediaItem, error)
 	GetTopTags(ctx context.Context, query *protobuf.Pagination) ([]TopTags, error)
+	GetRelatedTags(ctx context.Context, tags, excluded []string, since time.Time, limit int) ([]TopTags, error)
 	FindPostResponseById(ctx context.Context, id primitive.ObjectID, userId string) (PostResponse, error)
+	IncrementCounter(ctx context.Context, id primitive.ObjectID, counter Counter, delta int) error
+	ReconcileCounters(ctx context.Context) (int64, error)
+	SoftDelete(ctx context.Context, id primitive.ObjectID, userId string) error
+	Restore(ctx context.Context, id primitive.ObjectID) error
+	FindDeletedById(ctx context.Context, id primitive.ObjectID, data *Post) error
+	GetTrash(ctx context.Context, userId string, since time.Time, query *protobuf.Pagination) ([]PostResponse, error)
+	FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]Post, error)
+	FindDraftById(ctx context.Context, id primitive.ObjectID, data *Post) error
+	GetDrafts(ctx context.Context, userId string, query *protobuf.Pagination) ([]PostResponse, error)
+	UpdateDraft(ctx context.Context, data *Post) error
+	Publish(ctx context.Context, data *Post) error
+	FindDuePosts(ctx context.Context, now time.Time, after primitive.ObjectID, limit int) ([]Post, error)
+	Pin(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	Unpin(ctx context.Context, id primitive.ObjectID) error
+	CountPinned(ctx context.Context, userId string) (int64, error)
+	AddPollVotes(ctx context.Context, id primitive.ObjectID, options []int) error
+	Hide(ctx context.Context, id primitive.ObjectID, userId string) error
+	FindHiddenById(ctx context.Context, id primitive.ObjectID, data *Post) error
+	Unhide(ctx context.Context, id primitive.ObjectID) error
 }
 
+const (
+	Published = "Published"
+	Draft     = "Draft"
+	Scheduled = "Scheduled"
+)
+
+type Counter string
+
+const (
+	CountLike    Counter = "countLike"
+	CountComment Counter = "countComment"
+	CountShare   Counter = "countShare"
+)
+
 type PostRepoImpl struct {
 	base.BaseRepo
 	utils.QueryUtils
@@ -0,0 +1,22 @@
+package post
+
+import "go.mongodb.org/mongo-driver/bson"
+
+// VisibleFilter matches published posts that are neither in the trash nor
+// hidden by a moderator. Documents written before drafts existed have no
+// status and count as published. prefix is the path of the post inside the
+// document, e.g. "post." after a $lookup.
+func VisibleFilter(prefix string) bson.D {
+	return bson.D{
+		{Key: prefix + "deletedAt", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: prefix + "hiddenAt", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: prefix + "status", Value: bson.D{{Key: "$nin", Value: bson.A{Draft, Scheduled}}}},
+	}
+}
+
+func draftFilter() bson.D {
+	return bson.D{
+		{Key: "deletedAt", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: "status", Value: bson.D{{Key: "$in", Value: bson.A{Draft, Scheduled}}}},
+	}
+}
@@ -0,0 +1,112 @@
+package post
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"strings"
+
+	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	MediaImage = "Image"
+	MediaVideo = "Video"
+	MediaGif   = "Gif"
+
+	MAXALTLENGTH = 1000
+)
+
+// MediaRules limits what clients may attach to a post. Empty AllowedTypes and
+// a zero MaxPerPost or MaxSize disable that check. AllowedHosts is required,
+// the config is refused at startup without it.
+type MediaRules struct {
+	MaxPerPost   int
+	MaxSize      int64
+	AllowedTypes []string
+	AllowedHosts []string
+}
+
+func (r MediaRules) Validate(medias []Media) error {
+	if r.MaxPerPost > 0 && len(medias) > r.MaxPerPost {
+		return h.NewAppError(codes.InvalidArgument, fmt.Sprintf("A post can have at most %d media", r.MaxPerPost))
+	}
+
+	for _, media := range medias {
+		if err := r.validate(media); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r MediaRules) validate(media Media) error {
+	contentType := MediaType(media.Type)
+	if contentType == "" || (len(r.AllowedTypes) > 0 && !containsFold(r.AllowedTypes, contentType)) {
+		return h.NewAppError(codes.InvalidArgument, fmt.Sprintf("Media type %q is not allowed", media.Type))
+	}
+
+	link, err := url.Parse(media.Url)
+	if err != nil || (link.Scheme != "https" && link.Scheme != "http") || link.Hostname() == "" {
+		return h.NewAppError(codes.InvalidArgument, "Media url must be an absolute http(s) url")
+	}
+
+	if len(r.AllowedHosts) > 0 && !isAllowedHost(r.AllowedHosts, link.Hostname()) {
+		return h.NewAppError(codes.InvalidArgument, fmt.Sprintf("Media host %q is not allowed", link.Hostname()))
+	}
+
+	switch {
+	case media.Width < 0 || media.Height < 0 || media.Duration < 0 || media.Size < 0:
+		return h.NewAppError(codes.InvalidArgument, "Media dimensions, duration and size must not be negative")
+	case r.MaxSize > 0 && media.Size > r.MaxSize:
+		return h.NewAppError(codes.InvalidArgument, fmt.Sprintf("Media must not exceed %d bytes", r.MaxSize))
+	case len([]rune(media.Alt)) > MAXALTLENGTH:
+		return h.NewAppError(codes.InvalidArgument, fmt.Sprintf("Media alt text must not exceed %d characters", MAXALTLENGTH))
+	}
+	return nil
+}
+
+// MediaType returns the lower cased MIME type without parameters, or an empty
+// string when contentType cannot be parsed.
+func MediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// MediaKind groups a MIME type into MediaImage, MediaVideo or MediaGif.
+func MediaKind(contentType string) string {
+	switch mediaType := MediaType(contentType); {
+	case mediaType == "image/gif":
+		return MediaGif
+	case strings.HasPrefix(mediaType, "image/"):
+		return MediaImage
+	case strings.HasPrefix(mediaType, "video/"):
+		return MediaVideo
+	default:
+		return ""
+	}
+}
+
+func isAllowedHost(allowed []string, host string) bool {
+	host = strings.ToLower(host)
+	for _, data := range allowed {
+		data = strings.ToLower(data)
+		if host == data || strings.HasSuffix(host, "."+data) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(datas []string, val string) bool {
+	for _, data := range datas {
+		if strings.EqualFold(data, val) {
+			return true
+		}
+	}
+	return false
+}
@@ -7,9 +7,40 @@ import (
 )
 
 type Media struct {
-	Url  string `json:"url" bson:"url,omitempty"`
-	Type string `json:"type" bson:"type,omitempty"`
-	Id   string `json:"id" bson:"id,omitempty"`
+	Url       string  `json:"url" bson:"url,omitempty"`
+	Type      string  `json:"type" bson:"type,omitempty"`
+	Id        string  `json:"id" bson:"id,omitempty"`
+	Width     int     `json:"width" bson:"width,omitempty"`
+	Height    int     `json:"height" bson:"height,omitempty"`
+	Duration  float64 `json:"duration" bson:"duration,omitempty"` // seconds
+	Size      int64   `json:"size" bson:"size,omitempty"`         // bytes
+	Blurhash  string  `json:"blurhash" bson:"blurhash,omitempty"`
+	Alt       string  `json:"alt" bson:"alt,omitempty"`
+	Sensitive bool    `json:"sensitive" bson:"sensitive,omitempty"`
+}
+
+type LinkPreview struct {
+	Url         string `json:"url" bson:"url"`
+	Title       string `json:"title" bson:"title"`
+	Description string `json:"description" bson:"description,omitempty"`
+	Image       string `json:"image" bson:"image,omitempty"`
+	SiteName    string `json:"siteName" bson:"siteName,omitempty"`
+	Type        string `json:"type" bson:"type,omitempty"`
+}
+
+type PollOption struct {
+	Text  string `json:"text" bson:"text"`
+	Count int    `json:"count" bson:"count"`
+}
+
+type Poll struct {
+	Options        []PollOption `json:"options" bson:"options"`
+	MultipleChoice bool         `json:"multipleChoice" bson:"multipleChoice"`
+	ClosesAt       *time.Time   `json:"closesAt,omitempty" bson:"closesAt,omitempty"`
+	HideResults    bool         `json:"hideResults" bson:"hideResults"`
+	TotalVotes     int          `json:"totalVotes" bson:"totalVotes"`
+	MyVotes        []int        `json:"myVotes,omitempty" bson:"-"`
+	ResultsHidden  bool         `json:"resultsHidden" bson:"-"`
 }
 
 type Post struct {
@@ -17,11 +48,23 @@ type Post struct {
 	UserId       string             `json:"userId" bson:"userId,omitempty"`
 	Text         string             `json:"text" bson:"text"`
 	Media        []Media            `json:"media" bson:"media"`
+	Poll         *Poll              `json:"poll,omitempty" bson:"poll,omitempty"`
+	Previews     []LinkPreview      `json:"previews,omitempty" bson:"previews,omitempty"`
 	AllowComment bool               `json:"allowComment" bson:"allowComment" default:"true"`
 	CreatedAt    time.Time          `json:"createdAt" bson:"createdAt"`
 	UpdatedAt    time.Time          `json:"updatedAt" bson:"updatedAt"`
 	Tags         []string           `json:"tags" bson:"tags,omitempty"`
 	Privacy      string             `json:"privacy" bson:"privacy" default:"Public"`
+	CountLike    int                `json:"countLike" bson:"countLike"`
+	CountComment int                `json:"countComment" bson:"countComment"`
+	CountShare   int                `json:"countShare" bson:"countShare"`
+	Status       string             `json:"status" bson:"status,omitempty"`
+	PublishAt    *time.Time         `json:"publishAt,omitempty" bson:"publishAt,omitempty"`
+	PinnedAt     *time.Time         `json:"pinnedAt,omitempty" bson:"pinnedAt,omitempty"`
+	DeletedAt    *time.Time         `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	DeletedBy    string             `json:"deletedBy,omitempty" bson:"deletedBy,omitempty"`
+	HiddenAt     *time.Time         `json:"hiddenAt,omitempty" bson:"hiddenAt,omitempty"`
+	HiddenBy     string             `json:"hiddenBy,omitempty" bson:"hiddenBy,omitempty"`
 }
 
 type PostResponse struct {
@@ -29,6 +72,8 @@ type PostResponse struct {
 	UserId       string             `json:"userId" bson:"userId"`
 	Text         string             `json:"text" bson:"text"`
 	Media        []Media            `json:"media" bson:"media"`
+	Poll         *Poll              `json:"poll,omitempty" bson:"poll,omitempty"`
+	Previews     []LinkPreview      `json:"previews,omitempty" bson:"previews,omitempty"`
 	AllowComment bool               `json:"allowComment"`
 	CreatedAt    time.Time          `json:"createdAt" bson:"createdAt"`
 	UpdatedAt    time.Time          `json:"updatedAt" bson:"updatedAt"`
@@ -37,9 +82,13 @@ type PostResponse struct {
 	CountShare   int                `json:"countShare" bson:"countShare"`
 	IsLiked      bool               `json:"isLiked" bson:"isLiked"`
 	IsShared     bool               `json:"isShared" bson:"isShared"`
+	IsPinned     bool               `json:"isPinned" bson:"isPinned"`
 	Tags         []string           `json:"tags" bson:"tags"`
 	Privacy      string             `json:"privacy" bson:"privacy"`
 	TotalData    int                `json:"totalData" bson:"totalData"`
+	Status       string             `json:"status" bson:"status,omitempty"`
+	PublishAt    *time.Time         `json:"publishAt,omitempty" bson:"publishAt,omitempty"`
+	DeletedAt    *time.Time         `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
 }
 
 type TopTags struct {
@@ -47,3 +96,25 @@ type TopTags struct {
 	Count int                  `json:"count" bson:"count"`
 	Posts []primitive.ObjectID `json:"posts" bson:"posts"`
 }
+
+// MediaItem is a single attachment of a post, Index is its position in the
+// post media.
+type MediaItem struct {
+	PostId    primitive.ObjectID `json:"postId" bson:"postId"`
+	UserId    string             `json:"userId" bson:"userId"`
+	Index     int                `json:"index" bson:"index"`
+	Media     Media              `json:"media" bson:"media"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	TotalData int                `json:"totalData,omitempty" bson:"totalData"`
+}
+
+type MediaQuery struct {
+	Page       int
+	Limit      int
+	UserId     string
+	Kind       string
+	PostId     primitive.ObjectID
+	From       *time.Time
+	To         *time.Time
+	PublicOnly bool
+}
@@ -2,11 +2,15 @@ package post
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/forum-gamers/nine-tails-fox/database"
 	protobuf "github.com/forum-gamers/nine-tails-fox/generated/post"
 	h "github.com/forum-gamers/nine-tails-fox/helpers"
 	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
 	"github.com/forum-gamers/nine-tails-fox/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -14,11 +18,14 @@ import (
 	"google.golang.org/grpc/codes"
 )
 
-func NewPostRepo(q utils.QueryUtils) PostRepo {
-	return &PostRepoImpl{b.NewBaseRepo(b.GetCollection(b.Post)), q}
+func NewPostRepo(db database.Database, q utils.QueryUtils) PostRepo {
+	return &PostRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Post)), q}
 }
 
 func (r *PostRepoImpl) Create(ctx context.Context, data *Post) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.Create")
+	defer span.End()
+
 	result, err := r.BaseRepo.Create(ctx, data)
 	if err != nil {
 		return err
@@ -28,22 +35,37 @@ func (r *PostRepoImpl) Create(ctx context.Context, data *Post) error {
 }
 
 func (r *PostRepoImpl) FindById(ctx context.Context, id primitive.ObjectID, data *Post) error {
-	return r.FindOneById(ctx, id, data)
+	ctx, span := tracing.Start(ctx, "PostRepo.FindById")
+	defer span.End()
+
+	return r.FindOneByQuery(ctx, append(bson.D{{Key: "_id", Value: id}}, VisibleFilter("")...), data)
 }
 
-func (r *PostRepoImpl) GetSession() (mongo.Session, error) {
-	return r.BaseRepo.GetSession()
+func (r *PostRepoImpl) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.WithTransaction")
+	defer span.End()
+
+	return r.BaseRepo.WithTransaction(ctx, fn)
 }
 
 func (r *PostRepoImpl) DeleteOne(ctx context.Context, id primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.DeleteOne")
+	defer span.End()
+
 	return r.DeleteOneById(ctx, id)
 }
 
 func (r *PostRepoImpl) CreateMany(ctx context.Context, datas []any) (*mongo.InsertManyResult, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.CreateMany")
+	defer span.End()
+
 	return r.InsertMany(ctx, datas)
 }
 
-func (r *PostRepoImpl) GetPublicContent(ctx context.Context, userId string, query *protobuf.GetPostParams) ([]PostResponse, error) {
+func (r *PostRepoImpl) GetPublicContent(ctx context.Context, userId string, excluded, mutedTags []string, query *protobuf.GetPostParams) ([]PostResponse, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.GetPublicContent")
+	defer span.End()
+
 	now := time.Now().UTC()
 	orQuery := bson.A{}
 
@@ -64,16 +86,23 @@ func (r *PostRepoImpl) GetPublicContent(ctx context.Context, userId string, quer
 	}
 
 	orQuery = append(orQuery, bson.D{})
-	curr, err := r.BaseRepo.Aggregations(ctx, bson.A{
-		bson.D{{Key: "$match", Value: bson.D{
-			{Key: "createdAt",
-				Value: bson.D{
-					{Key: "$gte", Value: h.StartOfDay(now.AddDate(0, 0, -3))},
-				},
+	match := bson.D{
+		{Key: "createdAt",
+			Value: bson.D{
+				{Key: "$gte", Value: h.StartOfDay(now.AddDate(0, 0, -3))},
 			},
-			{Key: "privacy", Value: "Public"},
-			{Key: "$or", Value: orQuery},
-		}}},
+		},
+		{Key: "privacy", Value: "Public"},
+		{Key: "userId", Value: bson.D{{Key: "$nin", Value: excluded}}},
+		{Key: "$or", Value: orQuery},
+	}
+
+	if len(mutedTags) > 0 {
+		match = append(match, bson.E{Key: "tags", Value: bson.D{{Key: "$nin", Value: mutedTags}}})
+	}
+
+	curr, err := r.BaseRepo.Aggregations(ctx, bson.A{
+		bson.D{{Key: "$match", Value: append(match, VisibleFilter("")...)}},
 		bson.D{
 			{Key: "$facet",
 				Value: bson.D{
@@ -86,17 +115,13 @@ func (r *PostRepoImpl) GetPublicContent(ctx context.Context, userId string, quer
 						Value: bson.A{
 							r.NewSkip(int((query.Page - 1) * query.Limit)),
 							r.NewLimit(int(query.Limit)),
-							r.NewLookup("comment", "_id", "postId", "comment"),
-							r.NewLookup("like", "_id", "postId", "like"),
-							r.NewLookup("share", "_id", "postId", "share"),
+							r.NewUserLookup("like", "_id", "postId", userId, "like"),
+							r.NewUserLookup("share", "_id", "postId", userId, "share"),
 							bson.D{
 								{Key: "$addFields",
 									Value: bson.D{
-										{Key: "countLike", Value: bson.D{{Key: "$size", Value: "$like"}}},
-										{Key: "countShare", Value: bson.D{{Key: "$size", Value: "$share"}}},
-										r.NewCountComment("countComment", "$comment"),
-										r.IsDo("isLiked", "$like", userId),
-										r.IsDo("isShared", "$share", userId),
+										r.IsExists("isLiked", "$like"),
+										r.IsExists("isShared", "$share"),
 									},
 								},
 							},
@@ -114,6 +139,8 @@ func (r *PostRepoImpl) GetPublicContent(ctx context.Context, userId string, quer
 					{Key: "userId", Value: "$datas.userId"},
 					{Key: "text", Value: "$datas.text"},
 					{Key: "media", Value: "$datas.media"},
+					{Key: "poll", Value: "$datas.poll"},
+					{Key: "previews", Value: "$datas.previews"},
 					{Key: "allowComment", Value: "$datas.allowComment"},
 					{Key: "createdAt", Value: "$datas.createdAt"},
 					{Key: "updatedAt", Value: "$datas.updatedAt"},
@@ -151,38 +178,25 @@ func (r *PostRepoImpl) GetPublicContent(ctx context.Context, userId string, quer
 }
 
 func (r *PostRepoImpl) FindPostResponseById(ctx context.Context, id primitive.ObjectID, userId string) (result PostResponse, err error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.FindPostResponseById")
+	defer span.End()
+
 	cursor, err := r.Aggregations(ctx, bson.A{
-		bson.D{{Key: "$match", Value: bson.D{{Key: "_id", Value: id}}}},
-		r.NewLookup("comment", "_id", "postId", "comment"),
-		r.NewLookup("like", "_id", "postId", "like"),
-		r.NewLookup("share", "_id", "posId", "share"),
+		bson.D{{Key: "$match", Value: append(bson.D{{Key: "_id", Value: id}}, VisibleFilter("")...)}},
+		r.NewUserLookup("like", "_id", "postId", userId, "like"),
+		r.NewUserLookup("share", "_id", "postId", userId, "share"),
 		bson.D{
 			{Key: "$addFields",
 				Value: bson.D{
-					{Key: "countLike", Value: bson.D{{Key: "$size", Value: "$like"}}},
-					{Key: "countShare", Value: bson.D{{Key: "$size", Value: "$share"}}},
-					r.NewCountComment("countComment", "$comment"),
-					r.IsDo("isLiked", "$like", userId),
-					r.IsDo("isShared", "$share", userId),
+					r.IsExists("isLiked", "$like"),
+					r.IsExists("isShared", "$share"),
 				},
 			},
 		},
 		bson.D{
 			{Key: "$project", Value: bson.D{
-				{Key: "_id", Value: "_id"},
-				{Key: "userId", Value: "userId"},
-				{Key: "text", Value: "text"},
-				{Key: "media", Value: "media"},
-				{Key: "allowComment", Value: "allowComment"},
-				{Key: "createdAt", Value: "createdAt"},
-				{Key: "updatedAt", Value: "updatedAt"},
-				{Key: "countLike", Value: "countLike"},
-				{Key: "countComment", Value: "countComment"},
-				{Key: "countShare", Value: "countShare"},
-				{Key: "isLiked", Value: "isLiked"},
-				{Key: "isShared", Value: "isShared"},
-				{Key: "tags", Value: "tags"},
-				{Key: "privacy", Value: "privacy"},
+				{Key: "like", Value: 0},
+				{Key: "share", Value: 0},
 			}},
 		},
 	})
@@ -205,9 +219,12 @@ func (r *PostRepoImpl) FindPostResponseById(ctx context.Context, id primitive.Ob
 }
 
 func (r *PostRepoImpl) GetUserPost(ctx context.Context, userId string, query *protobuf.Pagination) ([]PostResponse, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.GetUserPost")
+	defer span.End()
+
 	curr, err := r.Aggregations(ctx, bson.A{
-		bson.D{{Key: "$match", Value: bson.D{{Key: "userId", Value: userId}}}},
-		bson.D{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}}}},
+		bson.D{{Key: "$match", Value: append(bson.D{{Key: "userId", Value: userId}}, VisibleFilter("")...)}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "pinnedAt", Value: -1}, {Key: "createdAt", Value: -1}}}},
 		bson.D{
 			{Key: "$facet",
 				Value: bson.D{
@@ -220,17 +237,13 @@ func (r *PostRepoImpl) GetUserPost(ctx context.Context, userId string, query *pr
 						Value: bson.A{
 							r.NewSkip(int((query.Page - 1) * query.Limit)),
 							r.NewLimit(int(query.Limit)),
-							r.NewLookup("comment", "_id", "postId", "comment"),
-							r.NewLookup("like", "_id", "postId", "like"),
-							r.NewLookup("share", "_id", "posId", "share"),
+							r.NewUserLookup("like", "_id", "postId", userId, "like"),
+							r.NewUserLookup("share", "_id", "postId", userId, "share"),
 							bson.D{
 								{Key: "$addFields",
 									Value: bson.D{
-										{Key: "countLike", Value: bson.D{{Key: "$size", Value: "$like"}}},
-										{Key: "countShare", Value: bson.D{{Key: "$size", Value: "$share"}}},
-										r.NewCountComment("countComment", "$comment"),
-										r.IsDo("isLiked", "$like", userId),
-										r.IsDo("isShared", "$share", userId),
+										r.IsExists("isLiked", "$like"),
+										r.IsExists("isShared", "$share"),
 									},
 								},
 							},
@@ -248,6 +261,8 @@ func (r *PostRepoImpl) GetUserPost(ctx context.Context, userId string, query *pr
 					{Key: "userId", Value: "$datas.userId"},
 					{Key: "text", Value: "$datas.text"},
 					{Key: "media", Value: "$datas.media"},
+					{Key: "poll", Value: "$datas.poll"},
+					{Key: "previews", Value: "$datas.previews"},
 					{Key: "allowComment", Value: "$datas.allowComment"},
 					{Key: "createdAt", Value: "$datas.createdAt"},
 					{Key: "updatedAt", Value: "$datas.updatedAt"},
@@ -256,6 +271,7 @@ func (r *PostRepoImpl) GetUserPost(ctx context.Context, userId string, query *pr
 					{Key: "countShare", Value: "$datas.countShare"},
 					{Key: "isLiked", Value: "$datas.isLiked"},
 					{Key: "isShared", Value: "$datas.isShared"},
+					{Key: "isPinned", Value: bson.D{{Key: "$gt", Value: bson.A{"$datas.pinnedAt", nil}}}},
 					{Key: "tags", Value: "$datas.tags"},
 					{Key: "privacy", Value: "$datas.privacy"},
 					{Key: "totalData", Value: "$total.total"},
@@ -285,13 +301,16 @@ func (r *PostRepoImpl) GetUserPost(ctx context.Context, userId string, query *pr
 }
 
 func (r *PostRepoImpl) GetUserPostMedia(ctx context.Context, userId string, query *protobuf.Pagination) ([]PostResponse, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.GetUserPostMedia")
+	defer span.End()
+
 	curr, err := r.Aggregations(ctx, bson.A{
 		bson.D{
 			{Key: "$match",
-				Value: bson.D{
+				Value: append(bson.D{
 					{Key: "userId", Value: userId},
-					{Key: "media", Value: bson.D{{Key: "$exists", Value: true}}},
-				},
+					{Key: "media.0", Value: bson.D{{Key: "$exists", Value: true}}},
+				}, VisibleFilter("")...),
 			},
 		},
 		bson.D{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}}}},
@@ -307,17 +326,13 @@ func (r *PostRepoImpl) GetUserPostMedia(ctx context.Context, userId string, quer
 						Value: bson.A{
 							r.NewSkip(int((query.Page - 1) * query.Limit)),
 							r.NewLimit(int(query.Limit)),
-							r.NewLookup("comment", "_id", "postId", "comment"),
-							r.NewLookup("like", "_id", "postId", "like"),
-							r.NewLookup("share", "_id", "posId", "share"),
+							r.NewUserLookup("like", "_id", "postId", userId, "like"),
+							r.NewUserLookup("share", "_id", "postId", userId, "share"),
 							bson.D{
 								{Key: "$addFields",
 									Value: bson.D{
-										{Key: "countLike", Value: bson.D{{Key: "$size", Value: "$like"}}},
-										{Key: "countShare", Value: bson.D{{Key: "$size", Value: "$share"}}},
-										r.NewCountComment("countComment", "$comment"),
-										r.IsDo("isLiked", "$like", userId),
-										r.IsDo("isShared", "$share", userId),
+										r.IsExists("isLiked", "$like"),
+										r.IsExists("isShared", "$share"),
 									},
 								},
 							},
@@ -335,6 +350,8 @@ func (r *PostRepoImpl) GetUserPostMedia(ctx context.Context, userId string, quer
 					{Key: "userId", Value: "$datas.userId"},
 					{Key: "text", Value: "$datas.text"},
 					{Key: "media", Value: "$datas.media"},
+					{Key: "poll", Value: "$datas.poll"},
+					{Key: "previews", Value: "$datas.previews"},
 					{Key: "allowComment", Value: "$datas.allowComment"},
 					{Key: "createdAt", Value: "$datas.createdAt"},
 					{Key: "updatedAt", Value: "$datas.updatedAt"},
@@ -372,9 +389,563 @@ func (r *PostRepoImpl) GetUserPostMedia(ctx context.Context, userId string, quer
 	return datas, nil
 }
 
+// GetMediaItems lists the individual attachments of a user's posts, newest
+// post first and in post order within a post.
+func (r *PostRepoImpl) GetMediaItems(ctx context.Context, query MediaQuery) ([]MediaItem, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.GetMediaItems")
+	defer span.End()
+
+	match := bson.D{
+		{Key: "userId", Value: query.UserId},
+		{Key: "media.0", Value: bson.D{{Key: "$exists", Value: true}}},
+	}
+
+	if !query.PostId.IsZero() {
+		match = append(match, bson.E{Key: "_id", Value: query.PostId})
+	}
+
+	if query.PublicOnly {
+		match = append(match, bson.E{Key: "privacy", Value: "Public"})
+	}
+
+	createdAt := bson.D{}
+	if query.From != nil {
+		createdAt = append(createdAt, bson.E{Key: "$gte", Value: *query.From})
+	}
+
+	if query.To != nil {
+		createdAt = append(createdAt, bson.E{Key: "$lte", Value: *query.To})
+	}
+
+	if len(createdAt) > 0 {
+		match = append(match, bson.E{Key: "createdAt", Value: createdAt})
+	}
+
+	pipeline := bson.A{
+		bson.D{{Key: "$match", Value: append(match, VisibleFilter("")...)}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}}},
+		bson.D{{Key: "$unwind", Value: bson.D{{Key: "path", Value: "$media"}, {Key: "includeArrayIndex", Value: "index"}}}},
+	}
+
+	if kind := mediaKindFilter(query.Kind); kind != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.D{{Key: "media.type", Value: kind}}}})
+	}
+
+	curr, err := r.Aggregations(ctx, append(pipeline,
+		bson.D{
+			{Key: "$facet", Value: bson.D{
+				{Key: "total", Value: bson.A{bson.D{{Key: "$count", Value: "total"}}}},
+				{Key: "datas", Value: bson.A{
+					r.NewSkip((query.Page - 1) * query.Limit),
+					r.NewLimit(query.Limit),
+				}},
+			}},
+		},
+		r.NewRawUnwind("$datas"),
+		r.NewRawUnwind("$total"),
+		bson.D{
+			{Key: "$project", Value: bson.D{
+				{Key: "_id", Value: 0},
+				{Key: "postId", Value: "$datas._id"},
+				{Key: "userId", Value: "$datas.userId"},
+				{Key: "index", Value: "$datas.index"},
+				{Key: "media", Value: "$datas.media"},
+				{Key: "createdAt", Value: "$datas.createdAt"},
+				{Key: "totalData", Value: "$total.total"},
+			}},
+		},
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	var datas []MediaItem
+	if err := curr.All(ctx, &datas); err != nil {
+		return nil, err
+	}
+
+	if len(datas) < 1 {
+		return datas, h.NewAppError(codes.NotFound, "data not found")
+	}
+	return datas, nil
+}
+
+// mediaKindFilter matches stored media types the way MediaKind does, ignoring
+// case and MIME parameters.
+func mediaKindFilter(kind string) any {
+	gif := primitive.Regex{Pattern: `^\s*image/gif\s*(;|$)`, Options: "i"}
+	switch kind {
+	case MediaImage:
+		return bson.D{{Key: "$regex", Value: primitive.Regex{Pattern: `^\s*image/`, Options: "i"}}, {Key: "$not", Value: gif}}
+	case MediaVideo:
+		return bson.D{{Key: "$regex", Value: primitive.Regex{Pattern: `^\s*video/`, Options: "i"}}}
+	case MediaGif:
+		return bson.D{{Key: "$regex", Value: gif}}
+	default:
+		return nil
+	}
+}
+
+func (r *PostRepoImpl) IncrementCounter(ctx context.Context, id primitive.ObjectID, counter Counter, delta int) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.IncrementCounter")
+	defer span.End()
+
+	result, err := r.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{string(counter): delta}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount < 1 {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return nil
+}
+
+// ReconcileCounters resets counters that drifted from the documents they
+// count. Each write only applies while the counters still hold the values the
+// recount was compared against, so an increment landing in between wins and
+// the post is looked at again on the next run.
+func (r *PostRepoImpl) ReconcileCounters(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.ReconcileCounters")
+	defer span.End()
+
+	countLookup := func(from, as string, match bson.D, total any) bson.D {
+		match = append(bson.D{{Key: "$expr", Value: bson.D{{Key: "$eq", Value: bson.A{"$postId", "$$postId"}}}}}, match...)
+		return bson.D{
+			{Key: "$lookup",
+				Value: bson.D{
+					{Key: "from", Value: from},
+					{Key: "let", Value: bson.D{{Key: "postId", Value: "$_id"}}},
+					{Key: "pipeline",
+						Value: bson.A{
+							bson.D{{Key: "$match", Value: match}},
+							bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: nil}, {Key: "total", Value: bson.D{{Key: "$sum", Value: total}}}}}},
+						},
+					},
+					{Key: "as", Value: as},
+				},
+			},
+		}
+	}
+
+	actual := func(field string) bson.D {
+		return bson.D{{Key: "$ifNull", Value: bson.A{bson.D{{Key: "$first", Value: field + ".total"}}, 0}}}
+	}
+
+	curr, err := r.Aggregations(ctx, bson.A{
+		countLookup("like", "like", nil, 1),
+		countLookup("share", "share", nil, 1),
+		countLookup("comment", "comment", comment.ActiveFilter(""), bson.D{
+			{Key: "$add", Value: bson.A{1, bson.D{
+				{Key: "$size", Value: bson.D{
+					{Key: "$filter", Value: bson.D{
+						{Key: "input", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$reply", bson.A{}}}}},
+						{Key: "cond", Value: comment.ActiveReplyCond()},
+					}},
+				}},
+			}}},
+		}),
+		bson.D{
+			{Key: "$project",
+				Value: bson.D{
+					{Key: "countLike", Value: actual("$like")},
+					{Key: "countShare", Value: actual("$share")},
+					{Key: "countComment", Value: actual("$comment")},
+					// null when missing so the filter below still matches
+					{Key: "stored", Value: bson.D{
+						{Key: "countLike", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$countLike", nil}}}},
+						{Key: "countShare", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$countShare", nil}}}},
+						{Key: "countComment", Value: bson.D{{Key: "$ifNull", Value: bson.A{"$countComment", nil}}}},
+					}},
+					{Key: "stale",
+						Value: bson.D{
+							{Key: "$or",
+								Value: bson.A{
+									bson.D{{Key: "$ne", Value: bson.A{"$countLike", actual("$like")}}},
+									bson.D{{Key: "$ne", Value: bson.A{"$countShare", actual("$share")}}},
+									bson.D{{Key: "$ne", Value: bson.A{"$countComment", actual("$comment")}}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		bson.D{{Key: "$match", Value: bson.D{{Key: "stale", Value: true}}}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer curr.Close(ctx)
+
+	var models []mongo.WriteModel
+	for curr.Next(ctx) {
+		var data struct {
+			Post   `bson:",inline"`
+			Stored bson.Raw `bson:"stored"`
+		}
+		if err := curr.Decode(&data); err != nil {
+			return 0, err
+		}
+
+		filter := bson.D{{Key: "_id", Value: data.Id}}
+		for _, counter := range []Counter{CountLike, CountShare, CountComment} {
+			filter = append(filter, bson.E{Key: string(counter), Value: data.Stored.Lookup(string(counter))})
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(filter).
+			SetUpdate(bson.M{"$set": bson.M{
+				string(CountLike):    data.CountLike,
+				string(CountShare):   data.CountShare,
+				string(CountComment): data.CountComment,
+			}}))
+	}
+
+	if len(models) < 1 {
+		return 0, nil
+	}
+
+	result, err := r.BulkUpdate(ctx, models)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+func (r *PostRepoImpl) SoftDelete(ctx context.Context, id primitive.ObjectID, userId string) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.SoftDelete")
+	defer span.End()
+
+	// a trashed post gives up its pin, restoring it must not go over the limit
+	result, err := r.UpdateOne(ctx, bson.M{"_id": id, "deletedAt": bson.M{"$exists": false}}, bson.M{
+		"$set": bson.M{
+			"deletedAt": time.Now(),
+			"deletedBy": userId,
+		},
+		"$unset": bson.M{"pinnedAt": ""},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount < 1 {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return nil
+}
+
+func (r *PostRepoImpl) Restore(ctx context.Context, id primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.Restore")
+	defer span.End()
+
+	result, err := r.UpdateOne(ctx, bson.M{"_id": id, "deletedAt": bson.M{"$exists": true}}, bson.M{
+		"$unset": bson.M{
+			"deletedAt": "",
+			"deletedBy": "",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount < 1 {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return nil
+}
+
+func (r *PostRepoImpl) FindDeletedById(ctx context.Context, id primitive.ObjectID, data *Post) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.FindDeletedById")
+	defer span.End()
+
+	return r.FindOneByQuery(ctx, bson.M{"_id": id, "deletedAt": bson.M{"$exists": true}}, data)
+}
+
+func (r *PostRepoImpl) GetTrash(ctx context.Context, userId string, since time.Time, query *protobuf.Pagination) ([]PostResponse, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.GetTrash")
+	defer span.End()
+
+	curr, err := r.Aggregations(ctx, bson.A{
+		bson.D{{Key: "$match", Value: bson.D{
+			{Key: "userId", Value: userId},
+			{Key: "deletedBy", Value: userId},
+			{Key: "deletedAt", Value: bson.D{{Key: "$gte", Value: since}}},
+		}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "deletedAt", Value: -1}}}},
+		bson.D{
+			{Key: "$facet",
+				Value: bson.D{
+					{Key: "total",
+						Value: bson.A{
+							bson.D{{Key: "$count", Value: "total"}},
+						},
+					},
+					{Key: "datas",
+						Value: bson.A{
+							r.NewSkip(int((query.Page - 1) * query.Limit)),
+							r.NewLimit(int(query.Limit)),
+						},
+					},
+				},
+			},
+		},
+		r.NewRawUnwind("$datas"),
+		r.NewRawUnwind("$total"),
+		bson.D{
+			{Key: "$project",
+				Value: bson.D{
+					{Key: "_id", Value: "$datas._id"},
+					{Key: "userId", Value: "$datas.userId"},
+					{Key: "text", Value: "$datas.text"},
+					{Key: "media", Value: "$datas.media"},
+					{Key: "poll", Value: "$datas.poll"},
+					{Key: "previews", Value: "$datas.previews"},
+					{Key: "allowComment", Value: "$datas.allowComment"},
+					{Key: "createdAt", Value: "$datas.createdAt"},
+					{Key: "updatedAt", Value: "$datas.updatedAt"},
+					{Key: "countLike", Value: "$datas.countLike"},
+					{Key: "countComment", Value: "$datas.countComment"},
+					{Key: "countShare", Value: "$datas.countShare"},
+					{Key: "tags", Value: "$datas.tags"},
+					{Key: "privacy", Value: "$datas.privacy"},
+					{Key: "deletedAt", Value: "$datas.deletedAt"},
+					{Key: "totalData", Value: "$total.total"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	var datas []PostResponse
+	for curr.Next(ctx) {
+		var data PostResponse
+		if err := curr.Decode(&data); err != nil {
+			return nil, err
+		}
+		datas = append(datas, data)
+	}
+
+	if len(datas) < 1 {
+		return datas, h.NewAppError(codes.NotFound, "data not found")
+	}
+
+	return datas, nil
+}
+
+func (r *PostRepoImpl) FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]Post, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.FindDeletedBefore")
+	defer span.End()
+
+	curr, err := r.Aggregations(ctx, bson.A{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "deletedAt", Value: bson.D{{Key: "$lt", Value: before}}}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "deletedAt", Value: 1}}}},
+		r.NewLimit(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	var datas []Post
+	for curr.Next(ctx) {
+		var data Post
+		if err := curr.Decode(&data); err != nil {
+			return nil, err
+		}
+		datas = append(datas, data)
+	}
+	return datas, nil
+}
+
+func (r *PostRepoImpl) FindDraftById(ctx context.Context, id primitive.ObjectID, data *Post) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.FindDraftById")
+	defer span.End()
+
+	return r.FindOneByQuery(ctx, append(bson.D{{Key: "_id", Value: id}}, draftFilter()...), data)
+}
+
+func (r *PostRepoImpl) GetDrafts(ctx context.Context, userId string, query *protobuf.Pagination) ([]PostResponse, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.GetDrafts")
+	defer span.End()
+
+	curr, err := r.Aggregations(ctx, bson.A{
+		bson.D{{Key: "$match", Value: append(bson.D{{Key: "userId", Value: userId}}, draftFilter()...)}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "updatedAt", Value: -1}}}},
+		bson.D{
+			{Key: "$facet",
+				Value: bson.D{
+					{Key: "total",
+						Value: bson.A{
+							bson.D{{Key: "$count", Value: "total"}},
+						},
+					},
+					{Key: "datas",
+						Value: bson.A{
+							r.NewSkip(int((query.Page - 1) * query.Limit)),
+							r.NewLimit(int(query.Limit)),
+						},
+					},
+				},
+			},
+		},
+		r.NewRawUnwind("$datas"),
+		r.NewRawUnwind("$total"),
+		bson.D{
+			{Key: "$project",
+				Value: bson.D{
+					{Key: "_id", Value: "$datas._id"},
+					{Key: "userId", Value: "$datas.userId"},
+					{Key: "text", Value: "$datas.text"},
+					{Key: "media", Value: "$datas.media"},
+					{Key: "poll", Value: "$datas.poll"},
+					{Key: "previews", Value: "$datas.previews"},
+					{Key: "allowComment", Value: "$datas.allowComment"},
+					{Key: "createdAt", Value: "$datas.createdAt"},
+					{Key: "updatedAt", Value: "$datas.updatedAt"},
+					{Key: "tags", Value: "$datas.tags"},
+					{Key: "privacy", Value: "$datas.privacy"},
+					{Key: "status", Value: "$datas.status"},
+					{Key: "publishAt", Value: "$datas.publishAt"},
+					{Key: "totalData", Value: "$total.total"},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	var datas []PostResponse
+	for curr.Next(ctx) {
+		var data PostResponse
+		if err := curr.Decode(&data); err != nil {
+			return nil, err
+		}
+		datas = append(datas, data)
+	}
+
+	if len(datas) < 1 {
+		return datas, h.NewAppError(codes.NotFound, "data not found")
+	}
+
+	return datas, nil
+}
+
+func (r *PostRepoImpl) UpdateDraft(ctx context.Context, data *Post) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.UpdateDraft")
+	defer span.End()
+
+	update := bson.M{
+		"$set": bson.M{
+			"text":         data.Text,
+			"media":        data.Media,
+			"allowComment": data.AllowComment,
+			"privacy":      data.Privacy,
+			"tags":         data.Tags,
+			"previews":     data.Previews,
+			"status":       data.Status,
+			"updatedAt":    data.UpdatedAt,
+		},
+	}
+	if data.PublishAt != nil {
+		update["$set"].(bson.M)["publishAt"] = data.PublishAt
+	} else {
+		update["$unset"] = bson.M{"publishAt": ""}
+	}
+
+	result, err := r.UpdateOne(ctx, append(bson.D{{Key: "_id", Value: data.Id}}, draftFilter()...), update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount < 1 {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return nil
+}
+
+// Publish turns a draft into a post, storing the text, tags and previews it
+// was checked with at publish time.
+func (r *PostRepoImpl) Publish(ctx context.Context, data *Post) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.Publish")
+	defer span.End()
+
+	set := bson.M{
+		"status":    Published,
+		"text":      data.Text,
+		"tags":      data.Tags,
+		"previews":  data.Previews,
+		"createdAt": data.CreatedAt,
+		"updatedAt": data.UpdatedAt,
+	}
+	if data.HiddenAt != nil {
+		set["hiddenAt"], set["hiddenBy"] = data.HiddenAt, data.HiddenBy
+	}
+
+	result, err := r.UpdateOne(ctx, append(bson.D{{Key: "_id", Value: data.Id}}, draftFilter()...), bson.M{
+		"$set":   set,
+		"$unset": bson.M{"publishAt": ""},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount < 1 {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return nil
+}
+
+// FindDuePosts pages through due scheduled posts in _id order, starting
+// after the given id so posts left due cannot hide the ones behind them.
+func (r *PostRepoImpl) FindDuePosts(ctx context.Context, now time.Time, after primitive.ObjectID, limit int) ([]Post, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.FindDuePosts")
+	defer span.End()
+
+	match := bson.D{
+		{Key: "status", Value: Scheduled},
+		{Key: "publishAt", Value: bson.D{{Key: "$lte", Value: now}}},
+		{Key: "deletedAt", Value: bson.D{{Key: "$exists", Value: false}}},
+	}
+	if !after.IsZero() {
+		match = append(match, bson.E{Key: "_id", Value: bson.D{{Key: "$gt", Value: after}}})
+	}
+
+	curr, err := r.Aggregations(ctx, bson.A{
+		bson.D{{Key: "$match", Value: match}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		r.NewLimit(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	var datas []Post
+	for curr.Next(ctx) {
+		var data Post
+		if err := curr.Decode(&data); err != nil {
+			return nil, err
+		}
+		datas = append(datas, data)
+	}
+	return datas, nil
+}
+
 func (r *PostRepoImpl) GetTopTags(ctx context.Context, query *protobuf.Pagination) ([]TopTags, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.GetTopTags")
+	defer span.End()
+
 	cursor, err := r.Aggregations(ctx, bson.A{
-		bson.D{{Key: "$match", Value: bson.D{{Key: "createdAt", Value: bson.D{{Key: "$gte", Value: h.StartOfDay(time.Now())}}}}}},
+		bson.D{{Key: "$match", Value: append(bson.D{
+			{Key: "createdAt", Value: bson.D{{Key: "$gte", Value: h.StartOfDay(time.Now())}}},
+		}, VisibleFilter("")...)}},
 		r.NewRawUnwind("$tags"),
 		bson.D{
 			{Key: "$group", Value: bson.D{
@@ -407,3 +978,166 @@ func (r *PostRepoImpl) GetTopTags(ctx context.Context, query *protobuf.Paginatio
 
 	return datas, nil
 }
+
+// GetRelatedTags counts the tags appearing next to any of tags on public posts
+// created since, leaving out excluded.
+func (r *PostRepoImpl) GetRelatedTags(ctx context.Context, tags, excluded []string, since time.Time, limit int) ([]TopTags, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.GetRelatedTags")
+	defer span.End()
+
+	cursor, err := r.Aggregations(ctx, bson.A{
+		bson.D{{Key: "$match", Value: append(bson.D{
+			{Key: "createdAt", Value: bson.D{{Key: "$gte", Value: since}}},
+			{Key: "privacy", Value: "Public"},
+			{Key: "tags", Value: bson.D{{Key: "$in", Value: tags}}},
+		}, VisibleFilter("")...)}},
+		r.NewRawUnwind("$tags"),
+		bson.D{{Key: "$match", Value: bson.D{{Key: "tags", Value: bson.D{{Key: "$nin", Value: excluded}}}}}},
+		bson.D{
+			{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: "$tags"},
+				{Key: "posts", Value: bson.D{{Key: "$addToSet", Value: "$_id"}}},
+			}},
+		},
+		bson.D{{Key: "$addFields", Value: bson.D{{Key: "count", Value: bson.D{{Key: "$size", Value: "$posts"}}}}}},
+		bson.D{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+		bson.D{{Key: "$limit", Value: limit}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var datas []TopTags
+	if err := cursor.All(ctx, &datas); err != nil {
+		return nil, err
+	}
+
+	if len(datas) < 1 {
+		return datas, h.NewAppError(codes.NotFound, "data not found")
+	}
+	return datas, nil
+}
+
+func (r *PostRepoImpl) Pin(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.Pin")
+	defer span.End()
+
+	result, err := r.UpdateOne(ctx, append(bson.D{{Key: "_id", Value: id}}, VisibleFilter("")...), bson.M{
+		"$set": bson.M{"pinnedAt": at},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount < 1 {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return nil
+}
+
+func (r *PostRepoImpl) Unpin(ctx context.Context, id primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.Unpin")
+	defer span.End()
+
+	result, err := r.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$unset": bson.M{"pinnedAt": ""},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount < 1 {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return nil
+}
+
+func (r *PostRepoImpl) CountPinned(ctx context.Context, userId string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "PostRepo.CountPinned")
+	defer span.End()
+
+	return r.CountDocuments(ctx, append(bson.D{
+		{Key: "userId", Value: userId},
+		{Key: "pinnedAt", Value: bson.D{{Key: "$exists", Value: true}}},
+	}, VisibleFilter("")...))
+}
+
+func (r *PostRepoImpl) AddPollVotes(ctx context.Context, id primitive.ObjectID, options []int) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.AddPollVotes")
+	defer span.End()
+
+	inc := bson.M{"poll.totalVotes": 1}
+	for _, option := range options {
+		inc[fmt.Sprintf("poll.options.%d.count", option)] = 1
+	}
+
+	result, err := r.UpdateOne(ctx, append(bson.D{
+		{Key: "_id", Value: id},
+		{Key: "poll", Value: bson.D{{Key: "$exists", Value: true}}},
+	}, VisibleFilter("")...), bson.M{"$inc": inc})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount < 1 {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return nil
+}
+
+func (r *PostRepoImpl) Hide(ctx context.Context, id primitive.ObjectID, userId string) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.Hide")
+	defer span.End()
+
+	result, err := r.UpdateOne(ctx, append(bson.D{{Key: "_id", Value: id}}, VisibleFilter("")...), bson.M{
+		"$set": bson.M{
+			"hiddenAt": time.Now(),
+			"hiddenBy": userId,
+		},
+		"$unset": bson.M{"pinnedAt": ""},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount < 1 {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return nil
+}
+
+func (r *PostRepoImpl) FindHiddenById(ctx context.Context, id primitive.ObjectID, data *Post) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.FindHiddenById")
+	defer span.End()
+
+	return r.FindOneByQuery(ctx, bson.D{
+		{Key: "_id", Value: id},
+		{Key: "deletedAt", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: "hiddenAt", Value: bson.D{{Key: "$exists", Value: true}}},
+	}, data)
+}
+
+func (r *PostRepoImpl) Unhide(ctx context.Context, id primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "PostRepo.Unhide")
+	defer span.End()
+
+	result, err := r.UpdateOne(ctx, bson.D{
+		{Key: "_id", Value: id},
+		{Key: "deletedAt", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: "hiddenAt", Value: bson.D{{Key: "$exists", Value: true}}},
+	}, bson.M{
+		"$unset": bson.M{
+			"hiddenAt": "",
+			"hiddenBy": "",
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount < 1 {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return nil
+}
@@ -36,7 +36,7 @@ func (s *PostServiceImpl) GetPostTags(text string) []string {
 	for _, p := range "!@#$%^&*)(_=+?.,;:'" {
 		modified = strings.ReplaceAll(modified, string(p), " ")
 	}
-	return strings.Split(modified, " ")
+	return strings.Split(strings.ToLower(modified), " ")
 }
 
 func (s *PostServiceImpl) CreatePostPayload(userId, text, privacy string, allowComment bool, media []Media, tags []string) Post {
@@ -49,5 +49,6 @@ func (s *PostServiceImpl) CreatePostPayload(userId, text, privacy string, allowC
 		UpdatedAt:    time.Now(),
 		Tags:         tags,
 		Privacy:      privacy,
+		Status:       Published,
 	}
 }
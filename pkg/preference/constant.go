@@ -2,20 +2,44 @@ package preference
 
 import (
 	"context"
+	"time"
 
 	"github.com/forum-gamers/nine-tails-fox/pkg/base"
 )
 
+const (
+	// weights added to every tag of a post a user interacts with
+	LIKEWEIGHT          = 1.0
+	NOTINTERESTEDWEIGHT = -2.0
+
+	// tags whose decayed weight falls below this are dropped on the next update
+	MINWEIGHT = 0.05
+
+	// suggestions come from posts of the last SUGGESTWINDOW that share one
+	// of the user's SUGGESTSEEDS strongest tags
+	SUGGESTWINDOW = 7 * 24 * time.Hour
+	SUGGESTSEEDS  = 10
+)
+
 type PreferenceRepo interface {
 	Create(ctx context.Context, userId string) (UserPreference, error)
 	FindByUserId(ctx context.Context, userId string) (UserPreference, error)
 	UpdateTags(ctx context.Context, userId string, tags []TagPreference) error
+	MuteTag(ctx context.Context, userId, tag string) error
+	UnmuteTag(ctx context.Context, userId, tag string) error
+	Reset(ctx context.Context, userId string) error
 }
 
 type PreferenceRepoImpl struct{ base.BaseRepo }
 
 type PreferenceService interface {
-	CreateUserNewTags(ctx context.Context, data UserPreference, newData []string) []TagPreference
+	AdjustTags(ctx context.Context, data UserPreference, tags []string, delta float64) []TagPreference
+	Ranked(data UserPreference) []TagPreference
+	RemoveTag(data UserPreference, tag string) []TagPreference
+	SuggestionSeeds(data UserPreference) (seeds []string, excluded []string)
 }
 
-type PreferenceServiceImpl struct{ Repo PreferenceRepo }
+type PreferenceServiceImpl struct {
+	Repo     PreferenceRepo
+	HalfLife time.Duration
+}
@@ -1,12 +1,39 @@
 package preference
 
-import "strings"
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+func NewUserPreference(userId string, now time.Time) UserPreference {
+	return UserPreference{
+		UserId:    userId,
+		Tags:      []TagPreference{},
+		MutedTags: []string{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
 
 func (t *UserPreference) IsContainsTag(tag string) bool {
-	for _, data := range t.Tags {
-		if strings.EqualFold(strings.ToLower(data.Value), strings.ToLower(tag)) {
-			return true
+	return t.IndexOfTag(tag) >= 0
+}
+
+func (t *UserPreference) IndexOfTag(tag string) int {
+	for i, data := range t.Tags {
+		if strings.EqualFold(data.Value, tag) {
+			return i
 		}
 	}
-	return false
+	return -1
+}
+
+// DecayedWeight halves the stored weight for every halfLife elapsed since it
+// was last updated.
+func (t TagPreference) DecayedWeight(halfLife time.Duration, now time.Time) float64 {
+	if halfLife <= 0 || !now.After(t.UpdatedAt) {
+		return t.Weight
+	}
+	return t.Weight * math.Pow(0.5, float64(now.Sub(t.UpdatedAt))/float64(halfLife))
 }
@@ -7,14 +7,19 @@ import (
 )
 
 type UserPreference struct {
-	Id        primitive.ObjectID `json:"_id" bson:"_id"`
+	Id        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
 	UserId    string             `json:"userId" bson:"userId"`
 	Tags      []TagPreference    `json:"tags" bson:"tags"`
+	MutedTags []string           `json:"mutedTags" bson:"mutedTags"`
 	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
 	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
 }
 
+// TagPreference holds the weight a tag had at UpdatedAt, it decays from
+// there with the configured half life.
 type TagPreference struct {
 	Value     string    `json:"value" bson:"value"`
+	Weight    float64   `json:"weight" bson:"weight"`
 	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
 }
@@ -4,23 +4,23 @@ import (
 	"context"
 	"time"
 
+	"github.com/forum-gamers/nine-tails-fox/database"
 	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
-func NewPreferenceRepo() PreferenceRepo {
-	return &PreferenceRepoImpl{b.NewBaseRepo(b.GetCollection(b.Preference))}
+func NewPreferenceRepo(db database.Database) PreferenceRepo {
+	return &PreferenceRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Preference))}
 }
 
 func (r *PreferenceRepoImpl) Create(ctx context.Context, userId string) (UserPreference, error) {
-	data := UserPreference{
-		UserId:    userId,
-		Tags:      []TagPreference{},
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
+	ctx, span := tracing.Start(ctx, "PreferenceRepo.Create")
+	defer span.End()
+
+	data := NewUserPreference(userId, time.Now())
 	if result, err := r.BaseRepo.Create(ctx, data); err != nil {
 		return data, err
 	} else {
@@ -29,22 +29,66 @@ func (r *PreferenceRepoImpl) Create(ctx context.Context, userId string) (UserPre
 	return data, nil
 }
 
+// FindByUserId returns an empty, unsaved preference for users without one, the
+// document is only created by the first write.
 func (r *PreferenceRepoImpl) FindByUserId(ctx context.Context, userId string) (data UserPreference, err error) {
+	ctx, span := tracing.Start(ctx, "PreferenceRepo.FindByUserId")
+	defer span.End()
+
 	err = r.FindOneByQuery(ctx, bson.M{"userId": userId}, &data)
 	if err != nil {
-		if e, ok := status.FromError(err); ok {
-			if e.Code() == codes.NotFound {
-				return r.Create(ctx, userId)
-			}
+		if e, ok := status.FromError(err); ok && e.Code() == codes.NotFound {
+			return NewUserPreference(userId, time.Now()), nil
 		}
 	}
 	return
 }
 
 func (r *PreferenceRepoImpl) UpdateTags(ctx context.Context, userId string, tags []TagPreference) error {
+	ctx, span := tracing.Start(ctx, "PreferenceRepo.UpdateTags")
+	defer span.End()
+
+	now := time.Now()
+	_, err := r.UpsertOne(ctx, bson.M{"userId": userId}, bson.M{
+		"$set":         bson.M{"tags": tags, "updatedAt": now},
+		"$setOnInsert": bson.M{"mutedTags": []string{}, "createdAt": now},
+	})
+	return err
+}
+
+func (r *PreferenceRepoImpl) MuteTag(ctx context.Context, userId, tag string) error {
+	ctx, span := tracing.Start(ctx, "PreferenceRepo.MuteTag")
+	defer span.End()
+
+	now := time.Now()
+	_, err := r.UpsertOne(ctx, bson.M{"userId": userId}, bson.M{
+		"$addToSet":    bson.M{"mutedTags": tag},
+		"$set":         bson.M{"updatedAt": now},
+		"$setOnInsert": bson.M{"tags": []TagPreference{}, "createdAt": now},
+	})
+	return err
+}
+
+func (r *PreferenceRepoImpl) UnmuteTag(ctx context.Context, userId, tag string) error {
+	ctx, span := tracing.Start(ctx, "PreferenceRepo.UnmuteTag")
+	defer span.End()
+
+	_, err := r.UpdateOne(ctx, bson.M{"userId": userId}, bson.M{
+		"$pull": bson.M{"mutedTags": tag},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+	return err
+}
+
+func (r *PreferenceRepoImpl) Reset(ctx context.Context, userId string) error {
+	ctx, span := tracing.Start(ctx, "PreferenceRepo.Reset")
+	defer span.End()
+
 	_, err := r.UpdateOne(ctx, bson.M{"userId": userId}, bson.M{
 		"$set": bson.M{
-			"tags": tags,
+			"tags":      []TagPreference{},
+			"mutedTags": []string{},
+			"updatedAt": time.Now(),
 		},
 	})
 	return err
@@ -2,22 +2,84 @@ package preference
 
 import (
 	"context"
+	"math"
+	"slices"
+	"sort"
+	"strings"
 	"time"
 )
 
-func NewPreferenceService(r PreferenceRepo) PreferenceService {
-	return &PreferenceServiceImpl{r}
+func NewPreferenceService(r PreferenceRepo, halfLife time.Duration) PreferenceService {
+	return &PreferenceServiceImpl{r, halfLife}
 }
 
-func (s *PreferenceServiceImpl) CreateUserNewTags(ctx context.Context, data UserPreference, newData []string) []TagPreference {
-	newTags := data.Tags
-	for _, newData := range newData {
-		if !data.IsContainsTag(newData) {
-			newTags = append(newTags, TagPreference{
-				Value:     newData,
-				CreatedAt: time.Now(),
-			})
+// AdjustTags decays every tag to now, adds delta to the given tags and drops
+// the ones that have faded out.
+func (s *PreferenceServiceImpl) AdjustTags(ctx context.Context, data UserPreference, tags []string, delta float64) []TagPreference {
+	now := time.Now()
+	data.Tags = slices.Clone(data.Tags)
+	for _, tag := range tags {
+		// stored tags are lower case so seeds and related tags match post tags
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+
+		if i := data.IndexOfTag(tag); i >= 0 {
+			data.Tags[i].Value = tag
+			data.Tags[i].Weight = data.Tags[i].DecayedWeight(s.HalfLife, now) + delta
+			data.Tags[i].UpdatedAt = now
+			continue
+		}
+
+		data.Tags = append(data.Tags, TagPreference{
+			Value:     tag,
+			Weight:    delta,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	newTags := make([]TagPreference, 0, len(data.Tags))
+	for _, tag := range data.Tags {
+		if math.Abs(tag.DecayedWeight(s.HalfLife, now)) >= MINWEIGHT {
+			newTags = append(newTags, tag)
 		}
 	}
 	return newTags
 }
+
+// Ranked returns the tags with their weights decayed to now, strongest first.
+func (s *PreferenceServiceImpl) Ranked(data UserPreference) []TagPreference {
+	now := time.Now()
+	result := make([]TagPreference, 0, len(data.Tags))
+	for _, tag := range data.Tags {
+		tag.Weight = tag.DecayedWeight(s.HalfLife, now)
+		result = append(result, tag)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Weight > result[j].Weight
+	})
+	return result
+}
+
+func (s *PreferenceServiceImpl) RemoveTag(data UserPreference, tag string) []TagPreference {
+	return slices.DeleteFunc(slices.Clone(data.Tags), func(current TagPreference) bool {
+		return strings.EqualFold(current.Value, tag)
+	})
+}
+
+// SuggestionSeeds returns the strongest positively weighted tags to look for
+// and every tag the user already engaged with or muted, which are never
+// suggested.
+func (s *PreferenceServiceImpl) SuggestionSeeds(data UserPreference) (seeds []string, excluded []string) {
+	excluded = append([]string{""}, data.MutedTags...)
+	for _, tag := range s.Ranked(data) {
+		excluded = append(excluded, tag.Value)
+		if tag.Weight > 0 && len(seeds) < SUGGESTSEEDS {
+			seeds = append(seeds, tag.Value)
+		}
+	}
+	return
+}
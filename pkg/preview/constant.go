@@ -0,0 +1,62 @@
+package preview
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+)
+
+const (
+	MAXREDIRECTS = 3
+
+	MAXTITLELENGTH       = 300
+	MAXDESCRIPTIONLENGTH = 1000
+)
+
+// Document is a fetched html page. Url is the address after redirects.
+type Document struct {
+	Url  string
+	Body []byte
+}
+
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (Document, error)
+}
+
+// Options configures the http fetcher. AllowPrivate disables the private
+// address check and must only be used in tests.
+type Options struct {
+	Timeout      time.Duration
+	MaxBytes     int64
+	AllowPrivate bool
+}
+
+type HTTPFetcher struct {
+	Client   *http.Client
+	MaxBytes int64
+}
+
+type PreviewRepo interface {
+	FindByUrls(ctx context.Context, urls []string) ([]Preview, error)
+	Upsert(ctx context.Context, data *Preview) error
+}
+
+type PreviewRepoImpl struct {
+	base.BaseRepo
+}
+
+type PreviewService interface {
+	ExtractUrls(text string) []string
+	Resolve(ctx context.Context, urls []string) []post.LinkPreview
+}
+
+type PreviewServiceImpl struct {
+	Repo       PreviewRepo
+	Fetcher    Fetcher
+	MaxLinks   int
+	CacheTTL   time.Duration
+	FailureTTL time.Duration
+}
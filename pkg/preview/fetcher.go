@@ -0,0 +1,127 @@
+package preview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+)
+
+var (
+	ErrPrivateAddress = errors.New("preview: refusing to connect to a private address")
+	ErrNotHTML        = errors.New("preview: response is not html")
+
+	// blockedPrefixes are ranges netip does not classify as private but that
+	// must not be reachable from a server side fetch either.
+	blockedPrefixes = []netip.Prefix{
+		netip.MustParsePrefix("0.0.0.0/8"),
+		netip.MustParsePrefix("100.64.0.0/10"),
+		netip.MustParsePrefix("192.0.0.0/24"),
+		netip.MustParsePrefix("198.18.0.0/15"),
+		netip.MustParsePrefix("240.0.0.0/4"),
+		netip.MustParsePrefix("64:ff9b::/96"),
+	}
+)
+
+// NewHTTPFetcher returns a Fetcher that only talks to public addresses. The
+// address is checked after DNS resolution for every connection, redirects
+// included, so a hostname cannot be used to reach an internal service.
+func NewHTTPFetcher(opts Options) Fetcher {
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	if !opts.AllowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+
+			if !IsPublicAddr(addrPort.Addr()) {
+				return ErrPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	return &HTTPFetcher{
+		Client: &http.Client{
+			Timeout: opts.Timeout,
+			Transport: &http.Transport{
+				Proxy:                  nil,
+				DialContext:            dialer.DialContext,
+				TLSHandshakeTimeout:    opts.Timeout,
+				ResponseHeaderTimeout:  opts.Timeout,
+				MaxResponseHeaderBytes: 64 << 10,
+				DisableKeepAlives:      true,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) > MAXREDIRECTS {
+					return fmt.Errorf("preview: stopped after %d redirects", MAXREDIRECTS)
+				}
+
+				if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+					return fmt.Errorf("preview: unsupported redirect scheme %q", req.URL.Scheme)
+				}
+				return nil
+			},
+		},
+		MaxBytes: opts.MaxBytes,
+	}
+}
+
+// IsPublicAddr reports whether addr is a globally routable unicast address.
+func IsPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *HTTPFetcher) Fetch(ctx context.Context, url string) (Document, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Document{}, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "nine-tails-fox-preview/1.0")
+
+	resp, err := f.Client.Do(req)
+	if err != nil {
+		return Document{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Document{}, fmt.Errorf("preview: unexpected status %d", resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return Document{}, ErrNotHTML
+	}
+
+	var reader io.Reader = resp.Body
+	if f.MaxBytes > 0 {
+		reader = io.LimitReader(resp.Body, f.MaxBytes)
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return Document{}, err
+	}
+
+	return Document{Url: resp.Request.URL.String(), Body: body}, nil
+}
@@ -0,0 +1,134 @@
+package preview_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/preview"
+)
+
+func newFetcher(allowPrivate bool, maxBytes int64) preview.Fetcher {
+	return preview.NewHTTPFetcher(preview.Options{
+		Timeout:      time.Second,
+		MaxBytes:     maxBytes,
+		AllowPrivate: allowPrivate,
+	})
+}
+
+func TestHTTPFetcher(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, "<html><head><title>Page</title></head></html>")
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, strings.Repeat("a", 1024))
+	})
+	mux.HandleFunc("/image", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprint(w, "png")
+	})
+	mux.HandleFunc("/missing", http.NotFound)
+	mux.HandleFunc("/redirect/", func(w http.ResponseWriter, r *http.Request) {
+		var n int
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/redirect/"), "%d", &n)
+		if n <= 0 {
+			http.Redirect(w, r, "/page", http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, fmt.Sprintf("/redirect/%d", n-1), http.StatusFound)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Run("fetches html", func(t *testing.T) {
+		doc, err := newFetcher(true, 0).Fetch(context.Background(), server.URL+"/page")
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+
+		if doc.Url != server.URL+"/page" || !strings.Contains(string(doc.Body), "<title>Page</title>") {
+			t.Errorf("Fetch() = %q %q", doc.Url, doc.Body)
+		}
+	})
+
+	t.Run("caps the body", func(t *testing.T) {
+		doc, err := newFetcher(true, 100).Fetch(context.Background(), server.URL+"/large")
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+
+		if len(doc.Body) != 100 {
+			t.Errorf("body has %d bytes, want 100", len(doc.Body))
+		}
+	})
+
+	t.Run("refuses non html", func(t *testing.T) {
+		_, err := newFetcher(true, 0).Fetch(context.Background(), server.URL+"/image")
+		if !errors.Is(err, preview.ErrNotHTML) {
+			t.Errorf("Fetch() error = %v, want %v", err, preview.ErrNotHTML)
+		}
+	})
+
+	t.Run("refuses error status", func(t *testing.T) {
+		if _, err := newFetcher(true, 0).Fetch(context.Background(), server.URL+"/missing"); err == nil {
+			t.Error("Fetch() error = nil, want status error")
+		}
+	})
+
+	t.Run("follows redirects up to the limit", func(t *testing.T) {
+		url := fmt.Sprintf("%s/redirect/%d", server.URL, preview.MAXREDIRECTS-1)
+		doc, err := newFetcher(true, 0).Fetch(context.Background(), url)
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+
+		if doc.Url != server.URL+"/page" {
+			t.Errorf("Fetch() url = %q, want %q", doc.Url, server.URL+"/page")
+		}
+	})
+
+	t.Run("stops after too many redirects", func(t *testing.T) {
+		url := fmt.Sprintf("%s/redirect/%d", server.URL, preview.MAXREDIRECTS)
+		_, err := newFetcher(true, 0).Fetch(context.Background(), url)
+		if err == nil || !strings.Contains(err.Error(), "redirects") {
+			t.Errorf("Fetch() error = %v, want redirect limit error", err)
+		}
+	})
+
+	t.Run("refuses loopback without AllowPrivate", func(t *testing.T) {
+		_, err := newFetcher(false, 0).Fetch(context.Background(), server.URL+"/page")
+		if !errors.Is(err, preview.ErrPrivateAddress) {
+			t.Errorf("Fetch() error = %v, want %v", err, preview.ErrPrivateAddress)
+		}
+	})
+}
+
+func TestIsPublicAddr(t *testing.T) {
+	tests := map[string]bool{
+		"8.8.8.8":          true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"10.0.0.1":         false,
+		"169.254.169.254":  false,
+		"100.64.0.1":       false,
+		"::1":              false,
+		"::ffff:127.0.0.1": false,
+		"fd00::1":          false,
+	}
+
+	for addr, want := range tests {
+		if got := preview.IsPublicAddr(netip.MustParseAddr(addr)); got != want {
+			t.Errorf("IsPublicAddr(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
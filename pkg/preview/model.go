@@ -0,0 +1,34 @@
+package preview
+
+import (
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Preview caches the Open Graph metadata of Url. Failed entries remember that
+// the url could not be previewed so it is not fetched again until ExpiresAt.
+type Preview struct {
+	Id          primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	Url         string             `json:"url" bson:"url"`
+	Title       string             `json:"title" bson:"title"`
+	Description string             `json:"description" bson:"description"`
+	Image       string             `json:"image" bson:"image"`
+	SiteName    string             `json:"siteName" bson:"siteName"`
+	Type        string             `json:"type" bson:"type"`
+	Failed      bool               `json:"failed" bson:"failed"`
+	FetchedAt   time.Time          `json:"fetchedAt" bson:"fetchedAt"`
+	ExpiresAt   time.Time          `json:"expiresAt" bson:"expiresAt"`
+}
+
+func (p Preview) ToLink() post.LinkPreview {
+	return post.LinkPreview{
+		Url:         p.Url,
+		Title:       p.Title,
+		Description: p.Description,
+		Image:       p.Image,
+		SiteName:    p.SiteName,
+		Type:        p.Type,
+	}
+}
@@ -0,0 +1,128 @@
+package preview
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Parse reads the Open Graph tags of an html document, falling back to the
+// <title> element and the description meta tag. Relative image urls are
+// resolved against doc.Url.
+func Parse(doc Document) Preview {
+	var (
+		data      = Preview{Url: doc.Url}
+		title     string
+		inTitle   bool
+		fallback  string
+		tokenizer = html.NewTokenizer(bytes.NewReader(doc.Body))
+	)
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return finish(data, title, fallback, doc.Url)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.DataAtom {
+			case atom.Title:
+				inTitle = title == ""
+			case atom.Meta:
+				key, content := metaOf(token)
+				switch key {
+				case "og:title":
+					data.Title = content
+				case "og:description":
+					data.Description = content
+				case "og:image", "og:image:url", "og:image:secure_url":
+					if data.Image == "" {
+						data.Image = content
+					}
+				case "og:site_name":
+					data.SiteName = content
+				case "og:type":
+					data.Type = content
+				case "description":
+					fallback = content
+				}
+			case atom.Body:
+				// Open Graph tags live in the head, there is nothing left to read.
+				return finish(data, title, fallback, doc.Url)
+			}
+		case html.TextToken:
+			if inTitle {
+				title += string(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			if tokenizer.Token().DataAtom == atom.Title {
+				inTitle = false
+			}
+		}
+	}
+}
+
+func metaOf(token html.Token) (key, content string) {
+	for _, attr := range token.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(attr.Val))
+			}
+		case "content":
+			content = attr.Val
+		}
+	}
+	return
+}
+
+func finish(data Preview, title, fallback, base string) Preview {
+	if data.Title == "" {
+		data.Title = title
+	}
+
+	if data.Description == "" {
+		data.Description = fallback
+	}
+
+	data.Title = clean(data.Title, MAXTITLELENGTH)
+	data.Description = clean(data.Description, MAXDESCRIPTIONLENGTH)
+	data.SiteName = clean(data.SiteName, MAXTITLELENGTH)
+	data.Type = clean(data.Type, MAXTITLELENGTH)
+	data.Image = resolve(base, strings.TrimSpace(data.Image))
+	return data
+}
+
+// clean collapses whitespace and cuts text to at most max runes.
+func clean(text string, max int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > max {
+		return string(runes[:max])
+	}
+	return text
+}
+
+// resolve makes link absolute and drops anything that is not http(s).
+func resolve(base, link string) string {
+	if link == "" {
+		return ""
+	}
+
+	baseUrl, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+
+	ref, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+
+	result := baseUrl.ResolveReference(ref)
+	if result.Scheme != "http" && result.Scheme != "https" {
+		return ""
+	}
+	return result.String()
+}
@@ -0,0 +1,96 @@
+package preview_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/preview"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		body string
+		want preview.Preview
+	}{
+		{
+			name: "open graph tags",
+			url:  "https://example.com/post",
+			body: `<html><head>
+				<title>Page title</title>
+				<meta property="og:title" content="OG title">
+				<meta property="og:description" content="OG description">
+				<meta property="og:image" content="https://cdn.example.com/a.png">
+				<meta property="og:site_name" content="Example">
+				<meta property="og:type" content="article">
+				<meta name="description" content="Meta description">
+			</head><body></body></html>`,
+			want: preview.Preview{
+				Url:         "https://example.com/post",
+				Title:       "OG title",
+				Description: "OG description",
+				Image:       "https://cdn.example.com/a.png",
+				SiteName:    "Example",
+				Type:        "article",
+			},
+		},
+		{
+			name: "falls back to title and description",
+			url:  "https://example.com/post",
+			body: `<html><head>
+				<title>
+					Page   title
+				</title>
+				<meta name="Description" content="Meta description">
+			</head><body></body></html>`,
+			want: preview.Preview{
+				Url:         "https://example.com/post",
+				Title:       "Page title",
+				Description: "Meta description",
+			},
+		},
+		{
+			name: "resolves relative image",
+			url:  "https://example.com/blog/post",
+			body: `<head><meta property="og:image" content="../img/cover.png"></head>`,
+			want: preview.Preview{
+				Url:   "https://example.com/blog/post",
+				Image: "https://example.com/img/cover.png",
+			},
+		},
+		{
+			name: "drops non http image",
+			url:  "https://example.com/post",
+			body: `<head><meta property="og:image" content="javascript:alert(1)"></head>`,
+			want: preview.Preview{Url: "https://example.com/post"},
+		},
+		{
+			name: "ignores tags after the head",
+			url:  "https://example.com/post",
+			body: `<head><title>Head</title></head><body><meta property="og:title" content="Body"></body>`,
+			want: preview.Preview{Url: "https://example.com/post", Title: "Head"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := preview.Parse(preview.Document{Url: tt.url, Body: []byte(tt.body)})
+			if got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTruncatesTitle(t *testing.T) {
+	title := strings.Repeat("é", preview.MAXTITLELENGTH+10)
+	got := preview.Parse(preview.Document{
+		Url:  "https://example.com",
+		Body: []byte(`<head><meta property="og:title" content="` + title + `"></head>`),
+	})
+
+	if n := len([]rune(got.Title)); n != preview.MAXTITLELENGTH {
+		t.Errorf("title has %d runes, want %d", n, preview.MAXTITLELENGTH)
+	}
+}
@@ -0,0 +1,41 @@
+package preview
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func NewPreviewRepo(db database.Database) PreviewRepo {
+	return &PreviewRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.LinkPreview))}
+}
+
+// FindByUrls returns the cached previews of urls that have not expired yet.
+func (r *PreviewRepoImpl) FindByUrls(ctx context.Context, urls []string) ([]Preview, error) {
+	ctx, span := tracing.Start(ctx, "PreviewRepo.FindByUrls")
+	defer span.End()
+
+	curr, err := r.FindByQuery(ctx, bson.M{"url": bson.M{"$in": urls}, "expiresAt": bson.M{"$gt": time.Now()}})
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	result := []Preview{}
+	if err := curr.All(ctx, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *PreviewRepoImpl) Upsert(ctx context.Context, data *Preview) error {
+	ctx, span := tracing.Start(ctx, "PreviewRepo.Upsert")
+	defer span.End()
+
+	_, err := r.UpsertOne(ctx, bson.M{"url": data.Url}, bson.M{"$set": data})
+	return err
+}
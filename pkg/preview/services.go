@@ -0,0 +1,125 @@
+package preview
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+)
+
+// Unlike filter.FindLinks, urlPattern requires an explicit scheme. Bare domains
+// are not worth a fetch.
+var urlPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"']+`)
+
+func NewPreviewService(r PreviewRepo, fetcher Fetcher, maxLinks int, cacheTTL, failureTTL time.Duration) PreviewService {
+	return &PreviewServiceImpl{r, fetcher, maxLinks, cacheTTL, failureTTL}
+}
+
+// ExtractUrls returns the distinct http(s) links in text, in order of
+// appearance and at most MaxLinks of them.
+func (s *PreviewServiceImpl) ExtractUrls(text string) []string {
+	urls := []string{}
+	if s.MaxLinks <= 0 {
+		return urls
+	}
+
+	seen := map[string]bool{}
+	for _, link := range urlPattern.FindAllString(text, -1) {
+		link = strings.TrimRight(link, ".,;:!?)]}'\"")
+		parsed, err := url.Parse(link)
+		if err != nil || parsed.Hostname() == "" {
+			continue
+		}
+
+		parsed.Scheme = strings.ToLower(parsed.Scheme)
+		parsed.Host = strings.ToLower(parsed.Host)
+		parsed.Fragment, parsed.RawFragment = "", ""
+		link = parsed.String()
+		if seen[link] {
+			continue
+		}
+
+		seen[link] = true
+		urls = append(urls, link)
+		if len(urls) == s.MaxLinks {
+			break
+		}
+	}
+	return urls
+}
+
+// Resolve returns the previews of urls in the same order, serving cached
+// entries and fetching the rest concurrently. Urls that cannot be previewed
+// are skipped, a failing link never fails the post.
+func (s *PreviewServiceImpl) Resolve(ctx context.Context, urls []string) []post.LinkPreview {
+	result := []post.LinkPreview{}
+	if len(urls) == 0 {
+		return result
+	}
+
+	cached, err := s.Repo.FindByUrls(ctx, urls)
+	if err != nil {
+		log.Printf("Link preview cache lookup failed : %s", err.Error())
+	}
+
+	previews := make(map[string]Preview, len(urls))
+	for _, data := range cached {
+		previews[data.Url] = data
+	}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, link := range urls {
+		if _, ok := previews[link]; ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func(link string) {
+			defer wg.Done()
+			data := s.fetch(ctx, link)
+			if err := s.Repo.Upsert(ctx, &data); err != nil {
+				log.Printf("Link preview cache write failed : %s", err.Error())
+			}
+
+			mu.Lock()
+			previews[link] = data
+			mu.Unlock()
+		}(link)
+	}
+	wg.Wait()
+
+	for _, link := range urls {
+		if data, ok := previews[link]; ok && !data.Failed && data.Title != "" {
+			result = append(result, data.ToLink())
+		}
+	}
+	return result
+}
+
+func (s *PreviewServiceImpl) fetch(ctx context.Context, link string) Preview {
+	now := time.Now()
+	doc, err := s.Fetcher.Fetch(ctx, link)
+	if err != nil {
+		return Preview{Url: link, Failed: true, FetchedAt: now, ExpiresAt: now.Add(s.FailureTTL)}
+	}
+
+	data := Parse(doc)
+	// Cache under the url written in the post so the next lookup hits it,
+	// even when the page redirected elsewhere.
+	data.Url = link
+	data.Failed = data.Title == ""
+	data.FetchedAt = now
+	data.ExpiresAt = now.Add(s.CacheTTL)
+	if data.Failed {
+		data.ExpiresAt = now.Add(s.FailureTTL)
+	}
+	return data
+}
@@ -0,0 +1,30 @@
+package relation
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+)
+
+const (
+	Block = "Block"
+	Mute  = "Mute"
+)
+
+type RelationRepo interface {
+	Create(ctx context.Context, data *Relation) error
+	FindOne(ctx context.Context, userId, targetId, relationType string) (Relation, error)
+	Delete(ctx context.Context, userId, targetId, relationType string) error
+	IsBlocked(ctx context.Context, userId, targetId string) (bool, error)
+	FindHiddenUserIds(ctx context.Context, userId string) ([]string, error)
+}
+
+type RelationRepoImpl struct {
+	base.BaseRepo
+}
+
+type RelationService interface {
+	CreatePayload(userId, targetId, relationType string) Relation
+}
+
+type RelationServiceImpl struct{}
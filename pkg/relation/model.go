@@ -0,0 +1,16 @@
+package relation
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Relation records that UserId blocked or muted TargetId.
+type Relation struct {
+	Id        primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	UserId    string             `json:"userId" bson:"userId"`
+	TargetId  string             `json:"targetId" bson:"targetId"`
+	Type      string             `json:"type" bson:"type"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
@@ -0,0 +1,100 @@
+package relation
+
+import (
+	"context"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+	"google.golang.org/grpc/codes"
+)
+
+func NewRelationRepo(db database.Database) RelationRepo {
+	return &RelationRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Relation))}
+}
+
+func (r *RelationRepoImpl) Create(ctx context.Context, data *Relation) error {
+	ctx, span := tracing.Start(ctx, "RelationRepo.Create")
+	defer span.End()
+
+	id, created, err := r.InsertIfNotExists(ctx, bson.M{"userId": data.UserId, "targetId": data.TargetId, "type": data.Type}, data)
+	if err != nil {
+		return err
+	}
+
+	if !created {
+		return h.NewAppError(codes.AlreadyExists, "Conflict")
+	}
+	data.Id = id
+	return nil
+}
+
+func (r *RelationRepoImpl) FindOne(ctx context.Context, userId, targetId, relationType string) (data Relation, err error) {
+	ctx, span := tracing.Start(ctx, "RelationRepo.FindOne")
+	defer span.End()
+
+	err = r.FindOneByQuery(ctx, bson.M{"userId": userId, "targetId": targetId, "type": relationType}, &data)
+	return
+}
+
+func (r *RelationRepoImpl) Delete(ctx context.Context, userId, targetId, relationType string) error {
+	ctx, span := tracing.Start(ctx, "RelationRepo.Delete")
+	defer span.End()
+
+	result, err := r.DeleteMany(ctx, bson.M{"userId": userId, "targetId": targetId, "type": relationType})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount < 1 {
+		return h.NewAppError(codes.NotFound, "Data not found")
+	}
+	return nil
+}
+
+// IsBlocked reports whether userId blocked targetId.
+func (r *RelationRepoImpl) IsBlocked(ctx context.Context, userId, targetId string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "RelationRepo.IsBlocked")
+	defer span.End()
+
+	count, err := r.CountDocuments(ctx, bson.M{"userId": userId, "targetId": targetId, "type": Block})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FindHiddenUserIds returns the users whose content userId should not see:
+// everyone they blocked or muted and everyone who blocked them.
+func (r *RelationRepoImpl) FindHiddenUserIds(ctx context.Context, userId string) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "RelationRepo.FindHiddenUserIds")
+	defer span.End()
+
+	curr, err := r.FindByQuery(ctx, bson.M{
+		"$or": bson.A{
+			bson.M{"userId": userId},
+			bson.M{"targetId": userId, "type": Block},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer curr.Close(ctx)
+
+	ids := []string{}
+	for curr.Next(ctx) {
+		var data Relation
+		if err := curr.Decode(&data); err != nil {
+			return nil, err
+		}
+
+		id := data.TargetId
+		if data.TargetId == userId {
+			id = data.UserId
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
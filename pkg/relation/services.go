@@ -0,0 +1,16 @@
+package relation
+
+import "time"
+
+func NewRelationService() RelationService {
+	return &RelationServiceImpl{}
+}
+
+func (s *RelationServiceImpl) CreatePayload(userId, targetId, relationType string) Relation {
+	return Relation{
+		UserId:    userId,
+		TargetId:  targetId,
+		Type:      relationType,
+		CreatedAt: time.Now(),
+	}
+}
@@ -3,15 +3,20 @@ package share
 import (
 	"context"
 
+	"github.com/forum-gamers/nine-tails-fox/database"
 	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-func NewShareRepo() ShareRepo {
-	return &ShareRepoImpl{b.NewBaseRepo(b.GetCollection(b.Share))}
+func NewShareRepo(db database.Database) ShareRepo {
+	return &ShareRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Share))}
 }
 
 func (r *ShareRepoImpl) DeleteMany(ctx context.Context, postId primitive.ObjectID) error {
+	ctx, span := tracing.Start(ctx, "ShareRepo.DeleteMany")
+	defer span.End()
+
 	return r.DeleteManyByQuery(ctx, bson.M{"postId": postId})
 }
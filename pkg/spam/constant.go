@@ -0,0 +1,55 @@
+package spam
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/pkg/base"
+)
+
+const (
+	Allow      = "Allow"
+	Quarantine = "Quarantine"
+	Reject     = "Reject"
+	RateLimit  = "RateLimit"
+)
+
+// score weights, a text reaching Limits.QuarantineScore is held for review
+// and one reaching Limits.RejectScore is refused
+const (
+	// per identical text the user already posted within the window
+	DUPLICATEWEIGHT = 40
+	// for a text made of nothing but links, scaled down by link density
+	LINKWEIGHT = 60
+	// per mention above MENTIONALLOWANCE
+	MENTIONWEIGHT    = 15
+	MENTIONALLOWANCE = 3
+	// per item posted within the window above half of Limits.MaxVelocity
+	VELOCITYWEIGHT = 5
+)
+
+type FingerprintRepo interface {
+	Create(ctx context.Context, data *Fingerprint) error
+	CountSince(ctx context.Context, userId, hash string, since time.Time) (int64, error)
+}
+
+type FingerprintRepoImpl struct {
+	base.BaseRepo
+}
+
+type SpamService interface {
+	Check(ctx context.Context, userId, text string) (Verdict, error)
+	Record(ctx context.Context, userId, targetType string, verdict Verdict) error
+}
+
+type SpamServiceImpl struct {
+	Repo   FingerprintRepo
+	Limits Limits
+}
+
+type Limits struct {
+	Window          time.Duration
+	MaxVelocity     int
+	QuarantineScore int
+	RejectScore     int
+}
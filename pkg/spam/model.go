@@ -0,0 +1,25 @@
+package spam
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Fingerprint remembers that a user posted a text, for duplicate and velocity
+// checks. It expires once it falls out of the scoring window.
+type Fingerprint struct {
+	Id         primitive.ObjectID `json:"_id" bson:"_id,omitempty"`
+	UserId     string             `json:"userId" bson:"userId"`
+	Hash       string             `json:"hash" bson:"hash"`
+	TargetType string             `json:"targetType" bson:"targetType"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	ExpiresAt  time.Time          `json:"expiresAt" bson:"expiresAt"`
+}
+
+type Verdict struct {
+	Outcome string
+	Score   int
+	Reasons []string
+	Hash    string
+}
@@ -0,0 +1,43 @@
+package spam
+
+import (
+	"context"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/database"
+	b "github.com/forum-gamers/nine-tails-fox/pkg/base"
+	"github.com/forum-gamers/nine-tails-fox/tracing"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func NewFingerprintRepo(db database.Database) FingerprintRepo {
+	return &FingerprintRepoImpl{b.NewBaseRepo(b.GetCollection(db, b.Fingerprint))}
+}
+
+func (r *FingerprintRepoImpl) Create(ctx context.Context, data *Fingerprint) error {
+	ctx, span := tracing.Start(ctx, "FingerprintRepo.Create")
+	defer span.End()
+
+	id, err := r.BaseRepo.Create(ctx, data)
+	if err != nil {
+		return err
+	}
+	data.Id = id
+	return nil
+}
+
+// CountSince counts what the user posted since the given time, only texts
+// with the given hash when it is not empty.
+func (r *FingerprintRepoImpl) CountSince(ctx context.Context, userId, hash string, since time.Time) (int64, error) {
+	ctx, span := tracing.Start(ctx, "FingerprintRepo.CountSince")
+	defer span.End()
+
+	filter := bson.D{
+		{Key: "userId", Value: userId},
+		{Key: "createdAt", Value: bson.D{{Key: "$gte", Value: since}}},
+	}
+	if hash != "" {
+		filter = append(filter, bson.E{Key: "hash", Value: hash})
+	}
+	return r.CountDocuments(ctx, filter)
+}
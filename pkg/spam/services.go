@@ -0,0 +1,105 @@
+package spam
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/forum-gamers/nine-tails-fox/filter"
+	h "github.com/forum-gamers/nine-tails-fox/helpers"
+	"google.golang.org/grpc/codes"
+)
+
+var mentionPattern = regexp.MustCompile(`(?:^|\s)@\w+`)
+
+func NewSpamService(repo FingerprintRepo, limits Limits) SpamService {
+	return &SpamServiceImpl{repo, limits}
+}
+
+// Check scores text the user is about to post. Going over the posting velocity
+// fails with ResourceExhausted and a score at the reject threshold fails with
+// InvalidArgument, otherwise the verdict says whether to quarantine.
+func (s *SpamServiceImpl) Check(ctx context.Context, userId, text string) (Verdict, error) {
+	verdict := Verdict{Outcome: Allow, Hash: Hash(text)}
+	since := time.Now().Add(-s.Limits.Window)
+
+	recent, err := s.Repo.CountSince(ctx, userId, "", since)
+	if err != nil {
+		return verdict, err
+	}
+
+	if s.Limits.MaxVelocity > 0 {
+		if recent >= int64(s.Limits.MaxVelocity) {
+			verdict.Outcome = RateLimit
+			return verdict, h.NewAppError(codes.ResourceExhausted, "Posting too fast, try again later")
+		}
+
+		if over := int(recent) - s.Limits.MaxVelocity/2; over > 0 {
+			verdict.add(over*VELOCITYWEIGHT, fmt.Sprintf("velocity:%d", recent))
+		}
+	}
+
+	if verdict.Hash != "" {
+		duplicates, err := s.Repo.CountSince(ctx, userId, verdict.Hash, since)
+		if err != nil {
+			return verdict, err
+		}
+
+		if duplicates > 0 {
+			verdict.add(int(duplicates)*DUPLICATEWEIGHT, fmt.Sprintf("duplicates:%d", duplicates))
+		}
+	}
+
+	if words := len(strings.Fields(text)); words > 0 {
+		if links := len(filter.FindLinks(text)); links > 0 {
+			verdict.add(LINKWEIGHT*links/words, fmt.Sprintf("links:%d/%d", links, words))
+		}
+	}
+
+	if mentions := len(mentionPattern.FindAllString(text, -1)); mentions > MENTIONALLOWANCE {
+		verdict.add((mentions-MENTIONALLOWANCE)*MENTIONWEIGHT, fmt.Sprintf("mentions:%d", mentions))
+	}
+
+	switch {
+	case s.Limits.RejectScore > 0 && verdict.Score >= s.Limits.RejectScore:
+		verdict.Outcome = Reject
+		return verdict, h.NewAppError(codes.InvalidArgument, "Content looks like spam")
+	case s.Limits.QuarantineScore > 0 && verdict.Score >= s.Limits.QuarantineScore:
+		verdict.Outcome = Quarantine
+	}
+	return verdict, nil
+}
+
+func (s *SpamServiceImpl) Record(ctx context.Context, userId, targetType string, verdict Verdict) error {
+	now := time.Now()
+	return s.Repo.Create(ctx, &Fingerprint{
+		UserId:     userId,
+		Hash:       verdict.Hash,
+		TargetType: targetType,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(s.Limits.Window),
+	})
+}
+
+func (v *Verdict) add(score int, reason string) {
+	if score < 1 {
+		return
+	}
+	v.Score += score
+	v.Reasons = append(v.Reasons, reason)
+}
+
+// Hash fingerprints text ignoring case and whitespace, empty text has no hash.
+func Hash(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	if normalized == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
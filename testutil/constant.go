@@ -0,0 +1,22 @@
+package testutil
+
+import (
+	"github.com/forum-gamers/nine-tails-fox/config"
+	"github.com/forum-gamers/nine-tails-fox/jobs"
+	"github.com/forum-gamers/nine-tails-fox/pkg/memory"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const BUFSIZE = 1024 * 1024
+
+type Server struct {
+	Config *config.Config
+	Store  *memory.Store
+	// Scheduler and Purger run the background jobs, tests call them directly
+	Scheduler jobs.Job
+	Purger    jobs.Job
+	Conn      *grpc.ClientConn
+	server    *grpc.Server
+	lis       *bufconn.Listener
+}
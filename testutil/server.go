@@ -0,0 +1,247 @@
+package testutil
+
+import (
+	"context"
+	"net"
+
+	"github.com/forum-gamers/nine-tails-fox/config"
+	cc "github.com/forum-gamers/nine-tails-fox/controllers"
+	"github.com/forum-gamers/nine-tails-fox/filter"
+	auditProto "github.com/forum-gamers/nine-tails-fox/generated/audit"
+	bookmarkProto "github.com/forum-gamers/nine-tails-fox/generated/bookmark"
+	commentProto "github.com/forum-gamers/nine-tails-fox/generated/comment"
+	likeProto "github.com/forum-gamers/nine-tails-fox/generated/like"
+	moderationProto "github.com/forum-gamers/nine-tails-fox/generated/moderation"
+	postProto "github.com/forum-gamers/nine-tails-fox/generated/post"
+	preferenceProto "github.com/forum-gamers/nine-tails-fox/generated/preference"
+	relationProto "github.com/forum-gamers/nine-tails-fox/generated/relation"
+	replyProto "github.com/forum-gamers/nine-tails-fox/generated/reply"
+	"github.com/forum-gamers/nine-tails-fox/interceptors"
+	"github.com/forum-gamers/nine-tails-fox/jobs"
+	"github.com/forum-gamers/nine-tails-fox/pkg/audit"
+	"github.com/forum-gamers/nine-tails-fox/pkg/bookmark"
+	"github.com/forum-gamers/nine-tails-fox/pkg/comment"
+	"github.com/forum-gamers/nine-tails-fox/pkg/media"
+	"github.com/forum-gamers/nine-tails-fox/pkg/memory"
+	"github.com/forum-gamers/nine-tails-fox/pkg/moderation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/poll"
+	"github.com/forum-gamers/nine-tails-fox/pkg/post"
+	"github.com/forum-gamers/nine-tails-fox/pkg/preference"
+	"github.com/forum-gamers/nine-tails-fox/pkg/preview"
+	"github.com/forum-gamers/nine-tails-fox/pkg/relation"
+	"github.com/forum-gamers/nine-tails-fox/pkg/reply"
+	"github.com/forum-gamers/nine-tails-fox/pkg/spam"
+	"github.com/golang-jwt/jwt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// NewServer starts the services on an in memory listener backed by the memory
+// store. opts can adjust the configuration before anything is wired.
+func NewServer(opts ...func(cfg *config.Config)) (*Server, error) {
+	cfg := config.Default()
+	cfg.Secret = "test-secret"
+	cfg.Database.Url = "memory://"
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	store := memory.NewStore()
+	postRepo := memory.NewPostRepo(store)
+	likeRepo := memory.NewLikeRepo(store)
+	commentRepo := memory.NewCommentRepo(store)
+	shareRepo := memory.NewShareRepo(store)
+	userPreferenceRepo := memory.NewPreferenceRepo(store)
+	bookmarkRepo := memory.NewBookmarkRepo(store)
+	idempotencyRepo := memory.NewIdempotencyRepo(store)
+	pollRepo := memory.NewPollRepo(store)
+	reportRepo := memory.NewReportRepo(store)
+	actionRepo := memory.NewActionRepo(store)
+	warningRepo := memory.NewWarningRepo(store)
+	auditRepo := memory.NewAuditRepo(store)
+	fingerprintRepo := memory.NewFingerprintRepo(store)
+	relationRepo := memory.NewRelationRepo(store)
+	linkPreviewRepo := memory.NewPreviewRepo(store)
+	lockRepo := memory.NewLockRepo(store)
+	mediaCleanupRepo := memory.NewCleanupRepo(store)
+
+	postService := post.NewPostService(postRepo)
+	userPreferenceService := preference.NewPreferenceService(userPreferenceRepo, cfg.Preference.HalfLife)
+	commentService := comment.NewCommentService(commentRepo)
+	bookmarkService := bookmark.NewBookMarkService(bookmarkRepo)
+	replyService := reply.NewReplyService(commentRepo)
+	pollService := poll.NewPollService(pollRepo)
+	moderationService := moderation.NewModerationService()
+	relationService := relation.NewRelationService()
+	mediaCleanupService := media.NewCleanupService(mediaCleanupRepo)
+	linkPreviewService := preview.NewPreviewService(linkPreviewRepo, preview.NewHTTPFetcher(preview.Options{
+		Timeout:  cfg.LinkPreview.Timeout,
+		MaxBytes: cfg.LinkPreview.MaxBytes,
+		// lets tests point posts at a local httptest server
+		AllowPrivate: true,
+	}), cfg.LinkPreview.MaxLinks, cfg.LinkPreview.CacheTTL, cfg.LinkPreview.FailureTTL)
+	spamService := spam.NewSpamService(fingerprintRepo, spam.Limits{
+		Window:          cfg.Spam.Window,
+		MaxVelocity:     cfg.Spam.MaxVelocity,
+		QuarantineScore: cfg.Spam.QuarantineScore,
+		RejectScore:     cfg.Spam.RejectScore,
+	})
+
+	contentFilter, err := filter.NewContentFilter(cfg.ContentFilter.RulesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	interceptor := interceptors.NewInterCeptor(&cfg, idempotencyRepo)
+	auditService := audit.NewAuditService(auditRepo, interceptor.GetRequestIdFromCtx)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptor.RequestId, interceptor.Recovery, interceptor.UnaryAuthentication, interceptor.Pagination, interceptor.Idempotency),
+	)
+
+	postController := &cc.PostService{
+		GetUser:        interceptor.GetUserFromCtx,
+		PostRepo:       postRepo,
+		PostService:    postService,
+		LikeRepo:       likeRepo,
+		CommentRepo:    commentRepo,
+		ShareRepo:      shareRepo,
+		PollRepo:       pollRepo,
+		PollService:    pollService,
+		TrashRetention: cfg.Trash.Retention,
+		MaxPins:        cfg.Post.MaxPins,
+		MediaRules: post.MediaRules{
+			MaxPerPost:   cfg.Media.MaxPerPost,
+			MaxSize:      cfg.Media.MaxSize,
+			AllowedTypes: cfg.Media.AllowedTypes,
+			AllowedHosts: cfg.Media.AllowedHosts,
+		},
+		AuditService:      auditService,
+		ContentFilter:     contentFilter,
+		ReportRepo:        reportRepo,
+		ModerationService: moderationService,
+		SpamService:       spamService,
+		RelationRepo:      relationRepo,
+		PreferenceRepo:    userPreferenceRepo,
+		PreviewService:    linkPreviewService,
+		LockRepo:          lockRepo,
+	}
+	postProto.RegisterPostServiceServer(grpcServer, postController)
+	likeProto.RegisterLikeServiceServer(grpcServer, &cc.LikeService{
+		GetUser:               interceptor.GetUserFromCtx,
+		LikeRepo:              likeRepo,
+		PostRepo:              postRepo,
+		UserPreferenceRepo:    userPreferenceRepo,
+		UserPreferenceService: userPreferenceService,
+		TrackPreference:       cfg.Features.PreferenceTracking,
+		AuditService:          auditService,
+		RelationRepo:          relationRepo,
+	})
+	commentProto.RegisterCommentServiceServer(grpcServer, &cc.CommentService{
+		GetUser:           interceptor.GetUserFromCtx,
+		PostRepo:          postRepo,
+		CommentRepo:       commentRepo,
+		CommentService:    commentService,
+		AuditService:      auditService,
+		ContentFilter:     contentFilter,
+		ReportRepo:        reportRepo,
+		ModerationService: moderationService,
+		SpamService:       spamService,
+		RelationRepo:      relationRepo,
+	})
+	bookmarkProto.RegisterBookmarkServiceServer(grpcServer, &cc.BookmarkService{
+		GetUser:         interceptor.GetUserFromCtx,
+		PostRepo:        postRepo,
+		BookmarkRepo:    bookmarkRepo,
+		BookmarkService: bookmarkService,
+		AuditService:    auditService,
+		RelationRepo:    relationRepo,
+	})
+	moderationProto.RegisterModerationServiceServer(grpcServer, &cc.ModerationService{
+		GetUser:           interceptor.GetUserFromCtx,
+		PostRepo:          postRepo,
+		CommentRepo:       commentRepo,
+		ReportRepo:        reportRepo,
+		ActionRepo:        actionRepo,
+		WarningRepo:       warningRepo,
+		ModerationService: moderationService,
+		AuditService:      auditService,
+	})
+	auditProto.RegisterAuditServiceServer(grpcServer, &cc.AuditService{
+		GetUser:   interceptor.GetUserFromCtx,
+		AuditRepo: auditRepo,
+	})
+	replyProto.RegisterReplyServiceServer(grpcServer, &cc.ReplyService{
+		GetUser:           interceptor.GetUserFromCtx,
+		PostRepo:          postRepo,
+		CommentRepo:       commentRepo,
+		CommentService:    commentService,
+		ReplyService:      replyService,
+		AuditService:      auditService,
+		ContentFilter:     contentFilter,
+		ReportRepo:        reportRepo,
+		ModerationService: moderationService,
+		SpamService:       spamService,
+		RelationRepo:      relationRepo,
+	})
+	relationProto.RegisterRelationServiceServer(grpcServer, &cc.RelationService{
+		GetUser:         interceptor.GetUserFromCtx,
+		PostRepo:        postRepo,
+		RelationRepo:    relationRepo,
+		RelationService: relationService,
+		AuditService:    auditService,
+	})
+	preferenceProto.RegisterPreferenceServiceServer(grpcServer, &cc.PreferenceService{
+		GetUser:               interceptor.GetUserFromCtx,
+		PostRepo:              postRepo,
+		UserPreferenceRepo:    userPreferenceRepo,
+		UserPreferenceService: userPreferenceService,
+		AuditService:          auditService,
+	})
+
+	lis := bufconn.Listen(BUFSIZE)
+	go grpcServer.Serve(lis)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		grpcServer.Stop()
+		return nil, err
+	}
+
+	return &Server{
+		Config:    &cfg,
+		Store:     store,
+		Scheduler: jobs.NewPostScheduler(postRepo, cfg.Jobs.PostSchedulerInterval, postController.PublishScheduled),
+		Purger:    jobs.NewTrashPurger(postRepo, likeRepo, commentRepo, shareRepo, pollRepo, auditService, cfg.Trash.Retention, cfg.Jobs.TrashPurgeInterval, mediaCleanupService.Enqueue),
+		Conn:      conn,
+		server:    grpcServer,
+		lis:       lis,
+	}, nil
+}
+
+func (s *Server) Close() {
+	s.Conn.Close()
+	s.server.Stop()
+	s.lis.Close()
+}
+
+func (s *Server) Token(userId, accountType string) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"id":          userId,
+		"accountType": accountType,
+	}).SignedString([]byte(s.Config.Secret))
+}
+
+func (s *Server) AuthContext(ctx context.Context, userId, accountType string) (context.Context, error) {
+	token, err := s.Token(userId, accountType)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.AppendToOutgoingContext(ctx, "access_token", token), nil
+}
@@ -0,0 +1,14 @@
+package tracing
+
+const (
+	TRACERNAME         = "github.com/forum-gamers/nine-tails-fox"
+	DEFAULTSERVICENAME = "nine-tails-fox"
+)
+
+type Exporter string
+
+const (
+	None   Exporter = "none"
+	Stdout Exporter = "stdout"
+	OTLP   Exporter = "otlp"
+)
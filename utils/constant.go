@@ -9,6 +9,8 @@ type QueryUtils interface {
 	NewLimit(val int) bson.D
 	IsDo(key, input, userId string) bson.E
 	NewCountComment(key, field string) bson.E
+	NewUserLookup(from, localField, foreignField, userId, as string) bson.D
+	IsExists(key, field string) bson.E
 }
 
 type QueryUtilsImpl struct{}
@@ -78,3 +78,42 @@ func (q *QueryUtilsImpl) NewCountComment(key, field string) bson.E {
 		},
 	}
 }
+
+func (q *QueryUtilsImpl) NewUserLookup(from, localField, foreignField, userId, as string) bson.D {
+	return bson.D{
+		{Key: "$lookup",
+			Value: bson.D{
+				{Key: "from", Value: from},
+				{Key: "let", Value: bson.D{{Key: "localId", Value: "$" + localField}}},
+				{Key: "pipeline",
+					Value: bson.A{
+						bson.D{
+							{Key: "$match",
+								Value: bson.D{
+									{Key: "userId", Value: userId},
+									{Key: "$expr", Value: bson.D{{Key: "$eq", Value: bson.A{"$" + foreignField, "$$localId"}}}},
+								},
+							},
+						},
+						bson.D{{Key: "$limit", Value: 1}},
+						bson.D{{Key: "$project", Value: bson.D{{Key: "_id", Value: 1}}}},
+					},
+				},
+				{Key: "as", Value: as},
+			},
+		},
+	}
+}
+
+func (q *QueryUtilsImpl) IsExists(key, field string) bson.E {
+	return bson.E{Key: key,
+		Value: bson.D{
+			{Key: "$gt",
+				Value: bson.A{
+					bson.D{{Key: "$size", Value: field}},
+					0,
+				},
+			},
+		},
+	}
+}